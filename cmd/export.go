@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/olusolaa/github-monitor/config"
+	"github.com/olusolaa/github-monitor/internal/container"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/utils"
+)
+
+// runExport exports the commits of a repository to a file, or to standard output when no file
+// is given, taking the same filters as the commits endpoint. It returns the exit code.
+//
+//	github-monitor export -owner chromium -name chromium -format ndjson -since 2024-08-01 -o commits.ndjson
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	owner := flags.String("owner", "", "owner of the repository (required)")
+	name := flags.String("name", "", "name of the repository (required)")
+	format := flags.String("format", domain.ExportFormatCSV, "export format: csv or ndjson")
+	output := flags.String("o", "", "file to write, standard output when empty")
+	branch := flags.String("branch", "", "only commits collected from this branch")
+	author := flags.String("author", "", "only commits whose author name or email matches")
+	since := flags.String("since", "", "only commits dated on or after this RFC 3339 time or YYYY-MM-DD date")
+	until := flags.String("until", "", "only commits dated on or before this RFC 3339 time or YYYY-MM-DD date")
+	query := flags.String("q", "", "full-text search of the commit messages")
+	path := flags.String("path", "", "only commits that changed this file or directory")
+	merge := flags.String("merge", "", "true for merge commits only, false to leave them out")
+	sort := flags.String("sort", domain.CommitSortNewest, "newest or oldest first")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *owner == "" || *name == "" {
+		fmt.Fprintln(os.Stderr, "export: -owner and -name are required")
+		flags.Usage()
+		return 2
+	}
+
+	filter := domain.CommitFilter{Branch: *branch, Author: *author, Query: *query, Path: *path, Sort: *sort}
+	var err error
+	if filter.Since, err = utils.ParseDate(*since, false); err != nil {
+		fmt.Fprintf(os.Stderr, "export: invalid -since: %v\n", err)
+		return 2
+	}
+	if filter.Until, err = utils.ParseDate(*until, true); err != nil {
+		fmt.Fprintf(os.Stderr, "export: invalid -until: %v\n", err)
+		return 2
+	}
+	if *merge != "" {
+		value, err := strconv.ParseBool(*merge)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: invalid -merge: %v\n", err)
+			return 2
+		}
+		filter.Merge = &value
+	}
+	if err := domain.ValidateExportFormat(*format); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 2
+	}
+
+	// The logger writes to standard output, which may be the export itself, so it stays off
+	cfg := config.LoadConfig()
+	diContainer := container.NewExportContainer(cfg)
+	defer diContainer.Close()
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	exported, err := diContainer.GetExportService().ExportCommits(context.Background(), *owner, *name, filter, *format, w)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d commits of %s/%s\n", exported, *owner, *name)
+	return 0
+}
@@ -18,6 +18,11 @@ import (
 )
 
 func main() {
+	// Run a one-off command instead of the server when one is given
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		os.Exit(runExport(os.Args[2:]))
+	}
+
 	// Load configuration
 	cfg := config.LoadConfig()
 	logger.InitLogger()
@@ -39,6 +44,12 @@ func main() {
 
 	// Register routes with the HTTP router
 	httpHandlers.RegisterRoutes(r, diContainer.GetRepoService(), diContainer.GetCommitService())
+	httpHandlers.RegisterOwnerRoutes(r, diContainer.GetOwnerService())
+	httpHandlers.RegisterAuthorRoutes(r, diContainer.GetCommitService())
+	httpHandlers.RegisterEnrichmentRoutes(r, diContainer.GetEnrichmentService())
+	httpHandlers.RegisterExportRoutes(r, diContainer.GetExportService())
+	httpHandlers.RegisterWebhookRoutes(r, diContainer.GetWebhookService(), cfg.WebhookSecret)
+	httpHandlers.RegisterAdminRoutes(r, diContainer.GetTokenPool(), diContainer.GetContributorService())
 
 	// Define and start the HTTP server
 	server := &http.Server{
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -11,6 +12,7 @@ import (
 type Config struct {
 	ServerAddress    string
 	GitHubToken      string
+	GitHubTokens     []string
 	PostgresUser     string
 	PostgresPassword string
 	PostgresDB       string
@@ -24,6 +26,33 @@ type Config struct {
 	DefaultOwner     string
 	DefaultRepo      string
 	GitHubBaseURL    string
+	WebhookSecret    string
+
+	// GitHub App authentication is used instead of GitHubToken when GitHubAppID is set.
+	GitHubAppID             int64
+	GitHubAppPrivateKeyPath string
+	GitHubAppInstallationID int64
+
+	// Adaptive polling shortens the interval of active repositories and backs off idle ones,
+	// within MinPollInterval and MaxPollInterval. Repositories with a schedule are not adapted.
+	AdaptivePolling bool
+	MinPollInterval time.Duration
+	MaxPollInterval time.Duration
+
+	// Repositories declared in RepositoriesFile replace DefaultOwner and DefaultRepo.
+	RepositoriesFile string
+	Repositories     []RepositoryConfig
+
+	// DiscoveryInterval is how often the repositories of monitored owners are listed again.
+	DiscoveryInterval time.Duration
+
+	// New commits are enriched with their line statistics and changed files every
+	// EnrichmentInterval, fetching at most EnrichmentConcurrency commits at a time.
+	EnrichmentInterval    time.Duration
+	EnrichmentConcurrency int
+
+	// MailmapFile is a .mailmap file imported at startup to merge the identities of authors.
+	MailmapFile string
 }
 
 func LoadConfig() *Config {
@@ -47,6 +76,12 @@ func LoadConfig() *Config {
 	viper.SetDefault("POSTGRES_USER", "postgres")
 	viper.SetDefault("POSTGRES_PASSWORD", "password")
 	viper.SetDefault("POSTGRES_DB", "postgres")
+	viper.SetDefault("ADAPTIVE_POLLING", false)
+	viper.SetDefault("MIN_POLL_INTERVAL", 300)   // 5 minutes in seconds
+	viper.SetDefault("MAX_POLL_INTERVAL", 86400) // 1 day in seconds
+	viper.SetDefault("DISCOVERY_INTERVAL", 3600) // 1 hour in seconds
+	viper.SetDefault("ENRICHMENT_INTERVAL", 0)   // Disabled
+	viper.SetDefault("ENRICHMENT_CONCURRENCY", 4)
 
 	if err := viper.ReadInConfig(); err != nil {
 		var configFileNotFoundError viper.ConfigFileNotFoundError
@@ -55,9 +90,19 @@ func LoadConfig() *Config {
 		}
 	}
 
+	repositoriesFile := viper.GetString("REPOSITORIES_FILE")
+	var repositories []RepositoryConfig
+	if repositoriesFile != "" {
+		var err error
+		if repositories, err = LoadRepositories(repositoriesFile); err != nil {
+			log.Fatalf("Invalid repositories file %s: %v", repositoriesFile, err)
+		}
+	}
+
 	return &Config{
 		ServerAddress:    viper.GetString("SERVER_ADDRESS"),
 		GitHubToken:      viper.GetString("GITHUB_TOKEN"),
+		GitHubTokens:     splitList(viper.GetString("GITHUB_TOKENS")),
 		PollInterval:     time.Duration(viper.GetInt("POLL_INTERVAL")) * time.Second,
 		MaxRetries:       viper.GetInt("MAX_RETRIES"),
 		InitialBackoff:   time.Duration(viper.GetInt("INITIAL_BACKOFF")) * time.Second,
@@ -67,9 +112,39 @@ func LoadConfig() *Config {
 		DefaultOwner:     viper.GetString("DEFAULT_OWNER"),
 		DefaultRepo:      viper.GetString("DEFAULT_REPO"),
 		GitHubBaseURL:    viper.GetString("GITHUB_BASE_URL"),
+		WebhookSecret:    viper.GetString("WEBHOOK_SECRET"),
 		PostgresUser:     viper.GetString("POSTGRES_USER"),
 		PostgresPassword: viper.GetString("POSTGRES_PASSWORD"),
 		PostgresDB:       viper.GetString("POSTGRES_DB"),
 		PostgresHost:     viper.GetString("POSTGRES_HOST"),
+
+		GitHubAppID:             viper.GetInt64("GITHUB_APP_ID"),
+		GitHubAppPrivateKeyPath: viper.GetString("GITHUB_APP_PRIVATE_KEY_PATH"),
+		GitHubAppInstallationID: viper.GetInt64("GITHUB_APP_INSTALLATION_ID"),
+
+		AdaptivePolling: viper.GetBool("ADAPTIVE_POLLING"),
+		MinPollInterval: time.Duration(viper.GetInt("MIN_POLL_INTERVAL")) * time.Second,
+		MaxPollInterval: time.Duration(viper.GetInt("MAX_POLL_INTERVAL")) * time.Second,
+
+		RepositoriesFile: repositoriesFile,
+		Repositories:     repositories,
+
+		DiscoveryInterval: time.Duration(viper.GetInt("DISCOVERY_INTERVAL")) * time.Second,
+
+		EnrichmentInterval:    time.Duration(viper.GetInt("ENRICHMENT_INTERVAL")) * time.Second,
+		EnrichmentConcurrency: viper.GetInt("ENRICHMENT_CONCURRENCY"),
+
+		MailmapFile: viper.GetString("MAILMAP_FILE"),
+	}
+}
+
+// splitList parses a comma separated list, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// RepositoryConfig declares a repository to monitor, or every repository of an owner when
+// Name is empty. Empty dates and schedule fall back to START_DATE, END_DATE and POLL_INTERVAL.
+// Branches are names or globs such as "release/*", the default branch is tracked when empty.
+type RepositoryConfig struct {
+	Owner     string   `yaml:"owner"`
+	Name      string   `yaml:"name"`
+	StartDate string   `yaml:"start_date"`
+	EndDate   string   `yaml:"end_date"`
+	Branches  []string `yaml:"branches"`
+	Schedule  string   `yaml:"schedule"`
+	Labels    []string `yaml:"labels"`
+}
+
+// repositoriesFile is the layout of the REPOSITORIES_FILE:
+//
+//	repositories:
+//	  - owner: golang
+//	    name: go
+//	    start_date: 2024-08-01
+//	    branches: [master, release-branch.*]
+//	    schedule: 15m
+//	    labels: [language]
+//	  - owner: my-org
+type repositoriesFile struct {
+	Repositories []RepositoryConfig `yaml:"repositories"`
+}
+
+// LoadRepositories reads and validates the repositories declared in a YAML file.
+func LoadRepositories(path string) ([]RepositoryConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repositories file: %w", err)
+	}
+	defer f.Close()
+
+	var file repositoriesFile
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse repositories file: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range file.Repositories {
+		repository := &file.Repositories[i]
+		if err := repository.validate(); err != nil {
+			return nil, fmt.Errorf("repository %d: %w", i+1, err)
+		}
+
+		key := strings.ToLower(repository.FullName())
+		if seen[key] {
+			return nil, fmt.Errorf("repository %d: %s is declared more than once", i+1, repository.FullName())
+		}
+		seen[key] = true
+	}
+	return file.Repositories, nil
+}
+
+// FullName returns owner/name, or just the owner for an owner-wide entry.
+func (r RepositoryConfig) FullName() string {
+	if r.Name == "" {
+		return r.Owner
+	}
+	return r.Owner + "/" + r.Name
+}
+
+func (r *RepositoryConfig) validate() error {
+	r.Owner = strings.TrimSpace(r.Owner)
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if strings.Contains(r.Owner, "/") || strings.Contains(r.Name, "/") {
+		return fmt.Errorf("owner and name must not contain '/'")
+	}
+	if r.Name == "" && (r.StartDate != "" || r.EndDate != "" || len(r.Branches) > 0 || r.Schedule != "" || len(r.Labels) > 0) {
+		return fmt.Errorf("settings of an owner-wide entry are not supported, declare its repositories by name")
+	}
+
+	start, err := parseDate(r.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date: %w", err)
+	}
+	end, err := parseDate(r.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date: %w", err)
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return fmt.Errorf("end_date must be after start_date")
+	}
+
+	if err := domain.ValidateBranchPatterns(r.Branches); err != nil {
+		return fmt.Errorf("invalid branches: %w", err)
+	}
+
+	if r.Schedule != "" {
+		if _, _, err := utils.ParseSchedule(r.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseDate accepts the same formats as START_DATE: YYYY-MM-DD or RFC 3339. An empty date
+// yields the zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
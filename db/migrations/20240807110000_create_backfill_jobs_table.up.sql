@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS backfill_jobs (
+    id SERIAL PRIMARY KEY,
+    repository_id INT NOT NULL,
+    since TEXT NOT NULL DEFAULT '',
+    until TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    last_page INT NOT NULL DEFAULT 0,
+    commits_saved INT NOT NULL DEFAULT 0,
+    last_commit_date TIMESTAMPTZ,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (repository_id) REFERENCES repositories(id)
+);
+
+-- Index for finding unfinished jobs at startup
+CREATE INDEX IF NOT EXISTS idx_backfill_jobs_status ON backfill_jobs(status);
+
+-- Index for looking up the jobs of a repository
+CREATE INDEX IF NOT EXISTS idx_backfill_jobs_repository_id ON backfill_jobs(repository_id);
@@ -0,0 +1 @@
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS monitoring_status TEXT NOT NULL DEFAULT 'active';
@@ -0,0 +1,3 @@
+-- Polling schedule of the repository: a duration such as 5m or a cron expression.
+-- Empty means the global POLL_INTERVAL.
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS schedule TEXT NOT NULL DEFAULT '';
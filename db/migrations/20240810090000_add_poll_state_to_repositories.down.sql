@@ -0,0 +1,2 @@
+ALTER TABLE repositories DROP COLUMN IF EXISTS empty_polls;
+ALTER TABLE repositories DROP COLUMN IF EXISTS poll_interval_seconds;
@@ -0,0 +1,4 @@
+-- Adaptive polling state: the interval currently computed for the repository, 0 until it
+-- has one, and the number of consecutive polls that found no new commits.
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS poll_interval_seconds INT NOT NULL DEFAULT 0;
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS empty_polls INT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE repositories DROP COLUMN IF EXISTS managed;
+ALTER TABLE repositories DROP COLUMN IF EXISTS labels;
+ALTER TABLE repositories DROP COLUMN IF EXISTS branch;
+ALTER TABLE repositories DROP COLUMN IF EXISTS end_date;
+ALTER TABLE repositories DROP COLUMN IF EXISTS start_date;
@@ -0,0 +1,8 @@
+-- Per repository settings declared in the repositories file. Empty dates fall back to
+-- START_DATE and END_DATE, an empty branch to the default branch. Managed repositories come
+-- from the repositories file and stop being monitored once they are removed from it.
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS start_date TEXT NOT NULL DEFAULT '';
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS end_date TEXT NOT NULL DEFAULT '';
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS branch TEXT NOT NULL DEFAULT '';
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS managed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,12 @@
+-- Users and organizations whose repositories are all monitored, with the filter selecting them.
+CREATE TABLE IF NOT EXISTS monitored_owners (
+    id SERIAL PRIMARY KEY,
+    owner TEXT NOT NULL UNIQUE,
+    include TEXT[] NOT NULL DEFAULT '{}',
+    exclude TEXT[] NOT NULL DEFAULT '{}',
+    languages TEXT[] NOT NULL DEFAULT '{}',
+    include_archived BOOLEAN NOT NULL DEFAULT FALSE,
+    include_forks BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
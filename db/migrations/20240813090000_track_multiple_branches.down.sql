@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS commit_branches;
+ALTER TABLE backfill_jobs DROP COLUMN IF EXISTS branch;
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS branch TEXT NOT NULL DEFAULT '';
+UPDATE repositories SET branch = branches[1] WHERE cardinality(branches) > 0;
+ALTER TABLE repositories DROP COLUMN IF EXISTS default_branch;
+ALTER TABLE repositories DROP COLUMN IF EXISTS branches;
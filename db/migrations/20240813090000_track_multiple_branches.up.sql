@@ -0,0 +1,32 @@
+-- Branches whose commits are collected, as names or globs such as release/*. Empty means the
+-- default branch.
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS branches TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS default_branch TEXT NOT NULL DEFAULT '';
+UPDATE repositories SET branches = ARRAY[branch] WHERE branch <> '';
+
+-- Branch a backfill job is collecting, its last_page refers to that branch.
+ALTER TABLE backfill_jobs ADD COLUMN IF NOT EXISTS branch TEXT NOT NULL DEFAULT '';
+
+-- Branches a commit was collected from. A commit is stored once however many branches contain it.
+-- The empty branch is the default branch while its name is not known yet.
+CREATE TABLE IF NOT EXISTS commit_branches (
+    commit_id INT NOT NULL REFERENCES commits(id) ON DELETE CASCADE,
+    branch TEXT NOT NULL,
+    PRIMARY KEY (commit_id, branch)
+);
+
+-- Existing commits were collected from the single branch of their repository.
+INSERT INTO commit_branches (commit_id, branch)
+SELECT c.id, r.branch
+FROM commits c
+JOIN repositories r ON r.id = c.repository_id
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE repositories DROP COLUMN IF EXISTS branch;
+
+-- Fetch every repository again rather than have it reported unchanged, so that the name of its
+-- default branch is recorded and its existing commits moved onto it.
+DELETE FROM http_cache_entries;
+
+-- Index for listing the commits of a branch
+CREATE INDEX IF NOT EXISTS idx_commit_branches_branch ON commit_branches(branch);
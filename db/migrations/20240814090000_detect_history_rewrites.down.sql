@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS history_rewrites;
+ALTER TABLE commits DROP COLUMN IF EXISTS unreachable;
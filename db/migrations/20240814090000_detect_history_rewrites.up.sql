@@ -0,0 +1,18 @@
+-- Commits that are no longer reachable from any tracked branch, such as after a force-push.
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS unreachable BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Rewrites of the history of a branch detected while monitoring it.
+CREATE TABLE IF NOT EXISTS history_rewrites (
+    id SERIAL PRIMARY KEY,
+    repository_id INT NOT NULL,
+    branch TEXT NOT NULL,
+    before_sha TEXT NOT NULL,
+    after_sha TEXT NOT NULL,
+    merge_base_sha TEXT NOT NULL DEFAULT '',
+    orphaned_commits INT NOT NULL DEFAULT 0,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (repository_id) REFERENCES repositories(id)
+);
+
+-- Index for listing the rewrites of a repository
+CREATE INDEX IF NOT EXISTS idx_history_rewrites_repository_id ON history_rewrites(repository_id);
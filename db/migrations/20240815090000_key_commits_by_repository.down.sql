@@ -0,0 +1,8 @@
+-- Hashes must be globally unique and SHA-1 sized again, so the copies of a commit in other
+-- repositories and SHA-256 commits are deleted.
+DROP INDEX IF EXISTS idx_commits_hash;
+ALTER TABLE commits DROP CONSTRAINT IF EXISTS commits_repository_id_hash_key;
+DELETE FROM commits a USING commits b WHERE a.hash = b.hash AND a.id > b.id;
+DELETE FROM commits WHERE length(hash) > 40;
+ALTER TABLE commits ALTER COLUMN hash TYPE VARCHAR(40);
+ALTER TABLE commits ADD CONSTRAINT commits_hash_key UNIQUE (hash);
@@ -0,0 +1,9 @@
+-- A commit is identified by its repository and hash, so that a fork and its upstream both keep
+-- the commits they share. Existing hashes are globally unique, so they are unique per
+-- repository too. Hashes are widened for SHA-256 object IDs.
+ALTER TABLE commits ALTER COLUMN hash TYPE VARCHAR(64);
+ALTER TABLE commits DROP CONSTRAINT IF EXISTS commits_hash_key;
+ALTER TABLE commits ADD CONSTRAINT commits_repository_id_hash_key UNIQUE (repository_id, hash);
+
+-- Index for looking up a commit by hash across repositories
+CREATE INDEX IF NOT EXISTS idx_commits_hash ON commits(hash);
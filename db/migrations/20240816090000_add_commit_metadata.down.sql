@@ -0,0 +1,12 @@
+ALTER TABLE commits DROP COLUMN IF EXISTS html_url;
+ALTER TABLE commits DROP COLUMN IF EXISTS verification_reason;
+ALTER TABLE commits DROP COLUMN IF EXISTS verified;
+ALTER TABLE commits DROP COLUMN IF EXISTS tree_sha;
+ALTER TABLE commits DROP COLUMN IF EXISTS parent_shas;
+ALTER TABLE commits DROP COLUMN IF EXISTS committer_id;
+ALTER TABLE commits DROP COLUMN IF EXISTS committer_login;
+ALTER TABLE commits DROP COLUMN IF EXISTS committer_email;
+ALTER TABLE commits DROP COLUMN IF EXISTS committer_name;
+ALTER TABLE commits DROP COLUMN IF EXISTS author_id;
+ALTER TABLE commits DROP COLUMN IF EXISTS author_login;
+ALTER TABLE commits DROP COLUMN IF EXISTS author_date;
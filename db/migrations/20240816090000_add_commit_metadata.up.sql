@@ -0,0 +1,18 @@
+-- The author and committer of a commit are stored separately, with the GitHub accounts matching
+-- them, along with the parents, tree and signature verification of the commit.
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS author_date TIMESTAMPTZ;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS author_login TEXT NOT NULL DEFAULT '';
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS author_id BIGINT;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS committer_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS committer_email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS committer_login TEXT NOT NULL DEFAULT '';
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS committer_id BIGINT;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS parent_shas TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS tree_sha VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS verification_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS html_url TEXT NOT NULL DEFAULT '';
+
+-- Commits stored so far recorded their committer as author. Their author is corrected, and
+-- author_date set, the next time they are collected.
+UPDATE commits SET committer_name = COALESCE(author_name, ''), committer_email = COALESCE(author_email, '');
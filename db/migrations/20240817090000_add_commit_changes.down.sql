@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS commit_files;
+DROP INDEX IF EXISTS idx_commits_enrichment_pending;
+ALTER TABLE commits DROP COLUMN IF EXISTS enrichment_status;
+ALTER TABLE commits DROP COLUMN IF EXISTS total_changes;
+ALTER TABLE commits DROP COLUMN IF EXISTS deletions;
+ALTER TABLE commits DROP COLUMN IF EXISTS additions;
@@ -0,0 +1,21 @@
+-- Line statistics and changed files of a commit are fetched by the enrichment stage. Commits
+-- stored so far are skipped until a backfill is requested, later ones wait to be enriched.
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS additions INT;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS deletions INT;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS total_changes INT;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS enrichment_status TEXT NOT NULL DEFAULT 'skipped';
+ALTER TABLE commits ALTER COLUMN enrichment_status SET DEFAULT 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_commits_enrichment_pending ON commits(commit_date) WHERE enrichment_status = 'pending';
+
+CREATE TABLE IF NOT EXISTS commit_files (
+    commit_id INT NOT NULL REFERENCES commits(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    status TEXT NOT NULL,
+    additions INT NOT NULL DEFAULT 0,
+    deletions INT NOT NULL DEFAULT 0,
+    previous_filename TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (commit_id, path)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_files_path ON commit_files(path);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_commits_author_email;
+DROP INDEX IF EXISTS idx_commits_author_name;
+DROP INDEX IF EXISTS idx_commits_message_search;
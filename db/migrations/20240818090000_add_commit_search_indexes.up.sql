@@ -0,0 +1,4 @@
+-- Commit messages are searched as English text, and authors by name or email regardless of case.
+CREATE INDEX IF NOT EXISTS idx_commits_message_search ON commits USING GIN (to_tsvector('english', message));
+CREATE INDEX IF NOT EXISTS idx_commits_author_name ON commits(lower(author_name));
+CREATE INDEX IF NOT EXISTS idx_commits_author_email ON commits(lower(author_email));
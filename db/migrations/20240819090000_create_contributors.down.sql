@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_commits_contributor_id;
+ALTER TABLE commits DROP COLUMN IF EXISTS contributor_id;
+DROP TABLE IF EXISTS contributor_aliases;
+DROP TABLE IF EXISTS contributors;
@@ -0,0 +1,32 @@
+-- Canonical identities of the authors of commits. Each is known by aliases matching the name,
+-- email and GitHub login of commit authors, empty fields matching any; emails and logins are
+-- stored lowercase. Commits are resolved to the contributor of their best matching alias.
+CREATE TABLE IF NOT EXISTS contributors (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    login TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contributors_login ON contributors(login) WHERE login <> '';
+CREATE INDEX IF NOT EXISTS idx_contributors_email ON contributors(email);
+
+CREATE TABLE IF NOT EXISTS contributor_aliases (
+    id SERIAL PRIMARY KEY,
+    contributor_id INT NOT NULL REFERENCES contributors(id) ON DELETE CASCADE,
+    name TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    login TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (name, email, login),
+    CHECK (email <> '' OR login <> '')
+);
+
+CREATE INDEX IF NOT EXISTS idx_contributor_aliases_contributor_id ON contributor_aliases(contributor_id);
+
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS contributor_id INT REFERENCES contributors(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_commits_contributor_id ON commits(contributor_id);
@@ -0,0 +1 @@
+ALTER TABLE monitored_owners DROP COLUMN IF EXISTS managed;
@@ -0,0 +1,2 @@
+-- Owners declared in the repositories file stop being monitored once they are removed from it.
+ALTER TABLE monitored_owners ADD COLUMN IF NOT EXISTS managed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Branches a backfill job has collected entirely, skipped when the job is resumed.
+ALTER TABLE backfill_jobs ADD COLUMN IF NOT EXISTS completed_branches TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1 @@
+-- Nothing to undo, the up migration queues no commits.
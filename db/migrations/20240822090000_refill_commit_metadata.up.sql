@@ -0,0 +1,3 @@
+-- Commits stored without their author date list their committer as author. They are not
+-- queued here, which would queue every such commit of every repository at once; queueing the
+-- commits of a repository for enrichment refills them instead.
@@ -0,0 +1,3 @@
+-- Failed attempts at enriching a commit. A commit failing too many times is marked failed
+-- instead of being retried by every enrichment pass.
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS enrichment_attempts INT NOT NULL DEFAULT 0;
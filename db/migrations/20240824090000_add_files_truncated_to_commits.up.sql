@@ -0,0 +1,2 @@
+-- Set on enriched commits changing more files than GitHub lists, whose commit_files are incomplete.
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS files_truncated BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,292 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/httpclient"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// appJWTLifetime stays under the 10 minute maximum GitHub accepts for app JWTs.
+	appJWTLifetime = 9 * time.Minute
+	// tokenRefreshMargin is how long before expiry a cached installation token is replaced.
+	tokenRefreshMargin = 5 * time.Minute
+	// installationsRefreshInterval limits how often an unknown owner triggers a new installation listing.
+	installationsRefreshInterval = time.Minute
+	installationsPageSize        = 100
+)
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type installation struct {
+	ID      int64 `json:"id"`
+	Account struct {
+		Login string `json:"login"`
+	} `json:"account"`
+}
+
+type installationsParams struct {
+	Page    int `url:"page"`
+	PerPage int `url:"per_page"`
+}
+
+// AppAuthenticator authenticates requests as a GitHub App installation. It mints installation
+// access tokens from a JWT signed with the app's private key and caches them until shortly
+// before they expire. The installation is chosen by the owner in the request path. Concurrent
+// requests needing the same token or installation listing share a single call to GitHub, which
+// is made without holding mu.
+type AppAuthenticator struct {
+	appID                 int64
+	privateKey            *rsa.PrivateKey
+	defaultInstallationID int64
+	httpClient            httpclient.HTTPClient
+	requestBuilder        *httpclient.RequestBuilder
+	responseHandler       *httpclient.ResponseHandler
+	now                   func() time.Time
+
+	mu                  sync.Mutex
+	installations       map[string]int64
+	installationsListed time.Time
+	tokens              map[int64]installationToken
+	calls               singleflight.Group
+}
+
+// NewAppAuthenticator creates an authenticator for the app identified by appID. Requests for
+// owners without a known installation fall back to defaultInstallationID when it is not zero.
+func NewAppAuthenticator(baseURL string, client httpclient.HTTPClient, appID int64, privateKeyPath string, defaultInstallationID int64) (*AppAuthenticator, error) {
+	privateKey, err := LoadPrivateKey(privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBuilder := httpclient.NewRequestBuilder(baseURL)
+	requestBuilder.SetHeader("Accept", "application/vnd.github+json")
+
+	return &AppAuthenticator{
+		appID:                 appID,
+		privateKey:            privateKey,
+		defaultInstallationID: defaultInstallationID,
+		httpClient:            client,
+		requestBuilder:        requestBuilder,
+		responseHandler:       httpclient.NewResponseHandler(),
+		now:                   time.Now,
+		installations:         make(map[string]int64),
+		tokens:                make(map[int64]installationToken),
+	}, nil
+}
+
+// LoadPrivateKey reads a PEM encoded RSA private key in PKCS#1 or PKCS#8 form.
+func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode private key %s: no PEM block found", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an RSA key", path)
+	}
+	return key, nil
+}
+
+// AuthMiddleware sets an installation access token on the request.
+func (a *AppAuthenticator) AuthMiddleware(req *http.Request, next httpclient.HTTPClient) (*http.Response, error) {
+	token, err := a.InstallationToken(req.Context(), ownerFromPath(req.URL.Path))
+	if err != nil {
+		return nil, errors.New("APP_AUTH_ERROR", "failed to obtain installation token", err, errors.Critical)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return next.Do(req)
+}
+
+// InstallationToken returns a valid access token for the installation covering owner.
+func (a *AppAuthenticator) InstallationToken(ctx context.Context, owner string) (string, error) {
+	installationID, err := a.installationFor(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	cached, ok := a.tokens[installationID]
+	a.mu.Unlock()
+	if ok && a.now().Before(cached.ExpiresAt.Add(-tokenRefreshMargin)) {
+		return cached.Token, nil
+	}
+
+	token, err, _ := a.calls.Do(fmt.Sprintf("token/%d", installationID), func() (interface{}, error) {
+		token, err := a.mintToken(ctx, installationID)
+		if err != nil {
+			return "", err
+		}
+		a.mu.Lock()
+		a.tokens[installationID] = *token
+		a.mu.Unlock()
+		return token.Token, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.(string), nil
+}
+
+// installationFor resolves owner to an installation ID, refreshing the installation list
+// when the owner has not been seen yet. Owners still unknown, including when the list cannot
+// be refreshed, fall back to the default installation.
+func (a *AppAuthenticator) installationFor(ctx context.Context, owner string) (int64, error) {
+	owner = strings.ToLower(owner)
+	a.mu.Lock()
+	id, ok := a.installations[owner]
+	refresh := !ok && owner != "" && a.now().Sub(a.installationsListed) >= installationsRefreshInterval
+	a.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	var refreshErr error
+	if refresh {
+		_, refreshErr, _ = a.calls.Do("installations", func() (interface{}, error) {
+			return nil, a.refreshInstallations(ctx)
+		})
+		a.mu.Lock()
+		id, ok = a.installations[owner]
+		a.mu.Unlock()
+		if ok {
+			return id, nil
+		}
+	}
+
+	if a.defaultInstallationID != 0 {
+		return a.defaultInstallationID, nil
+	}
+	if refreshErr != nil {
+		return 0, refreshErr
+	}
+	return 0, fmt.Errorf("no GitHub App installation found for owner %q", owner)
+}
+
+// refreshInstallations lists the installations of the app. A failed listing is not retried
+// before installationsRefreshInterval has passed either.
+func (a *AppAuthenticator) refreshInstallations(ctx context.Context) error {
+	listed := make(map[string]int64)
+	for page := 1; ; page++ {
+		var installations []installation
+		params := installationsParams{Page: page, PerPage: installationsPageSize}
+		if err := a.appRequest(ctx, http.MethodGet, "/app/installations", params, &installations); err != nil {
+			a.mu.Lock()
+			a.installationsListed = a.now()
+			a.mu.Unlock()
+			return fmt.Errorf("failed to list app installations: %w", err)
+		}
+
+		for _, inst := range installations {
+			listed[strings.ToLower(inst.Account.Login)] = inst.ID
+		}
+		if len(installations) < installationsPageSize {
+			break
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for owner, id := range listed {
+		a.installations[owner] = id
+	}
+	a.installationsListed = a.now()
+	return nil
+}
+
+func (a *AppAuthenticator) mintToken(ctx context.Context, installationID int64) (*installationToken, error) {
+	var token installationToken
+	path := fmt.Sprintf("/app/installations/%d/access_tokens", installationID)
+	if err := a.appRequest(ctx, http.MethodPost, path, nil, &token); err != nil {
+		return nil, fmt.Errorf("failed to create installation token: %w", err)
+	}
+	return &token, nil
+}
+
+// appRequest performs a request authenticated as the app itself.
+func (a *AppAuthenticator) appRequest(ctx context.Context, method, path string, params interface{}, out interface{}) error {
+	jwt, err := a.signJWT()
+	if err != nil {
+		return err
+	}
+
+	req, err := a.requestBuilder.BuildRequest(ctx, method, path, params, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	return a.responseHandler.HandleResponse(resp, out)
+}
+
+// signJWT creates the RS256 JWT identifying the app.
+func (a *AppAuthenticator) signJWT() (string, error) {
+	now := a.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(), // allow for clock drift
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": a.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ownerFromPath extracts the account from /repos/{owner}/..., /orgs/{org}/... and /users/{user}/... paths,
+// allowing for a base path such as /api/v3 on GitHub Enterprise.
+func ownerFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "repos", "orgs", "users":
+			return segments[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/httpclient"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// ErrNotModified is returned when GitHub reports that a resource has not changed
+// since the validators cached for its URL were recorded.
+var ErrNotModified = httpclient.ErrNotModified
+
+// ValidatorStore persists ETag/Last-Modified validators keyed by request URL.
+type ValidatorStore interface {
+	Get(ctx context.Context, url string) (*domain.HTTPCacheEntry, error)
+	Upsert(ctx context.Context, entry *domain.HTTPCacheEntry) error
+}
+
+type conditionalKey struct{}
+
+// WithConditionalRequests marks ctx so that requests made with it send the cached
+// validators and report a 304 response as ErrNotModified.
+func WithConditionalRequests(ctx context.Context) context.Context {
+	return context.WithValue(ctx, conditionalKey{}, true)
+}
+
+func conditionalEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(conditionalKey{}).(bool)
+	return enabled
+}
+
+// conditionalCache applies and records validators for conditional GET requests.
+// Failures talking to the store are logged and never fail the request itself.
+type conditionalCache struct {
+	store ValidatorStore
+}
+
+func newConditionalCache(store ValidatorStore) *conditionalCache {
+	return &conditionalCache{store: store}
+}
+
+// Apply sets If-None-Match/If-Modified-Since on req from the cached validators.
+func (c *conditionalCache) Apply(ctx context.Context, req *http.Request) {
+	if c.store == nil || !conditionalEnabled(ctx) {
+		return
+	}
+
+	entry, err := c.store.Get(ctx, req.URL.String())
+	if err != nil {
+		logger.LogWarning(fmt.Sprintf("failed to load cached validators for %s: %v", req.URL, err))
+		return
+	}
+	if entry == nil {
+		return
+	}
+
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// Store records the validators returned with a successful response to req.
+func (c *conditionalCache) Store(ctx context.Context, req *http.Request, resp *http.Response) {
+	if c.store == nil || !conditionalEnabled(ctx) {
+		return
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+
+	entry := &domain.HTTPCacheEntry{
+		URL:          req.URL.String(),
+		ETag:         etag,
+		LastModified: lastModified,
+	}
+	if err := c.store.Upsert(ctx, entry); err != nil {
+		logger.LogWarning(fmt.Sprintf("failed to store validators for %s: %v", req.URL, err))
+	}
+}
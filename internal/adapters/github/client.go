@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"github.com/olusolaa/github-monitor/internal/adapters/github/pagination"
 	"github.com/olusolaa/github-monitor/pkg/errors"
@@ -9,32 +10,42 @@ import (
 	"net/http"
 )
 
+// ErrNotFound is returned when GitHub reports that a requested resource does not exist.
+var ErrNotFound = stderrors.New("not found")
+
 // Client represents a GitHub API client.
 type Client struct {
 	httpClient        *httpclient.Client
 	requestBuilder    *httpclient.RequestBuilder
 	responseHandler   *httpclient.ResponseHandler
 	paginationManager *pagination.Manager
+	cache             *conditionalCache
 }
 
 // NewClient creates a new GitHub API client with custom HTTP client settings.
-func NewClient(baseURL string, client *httpclient.Client) *Client {
+// Validators for conditional requests are kept in store, which may be nil to disable caching.
+func NewClient(baseURL string, client *httpclient.Client, store ValidatorStore) *Client {
 	customClient := client
 	requestBuilder := httpclient.NewRequestBuilder(baseURL)
 	responseHandler := httpclient.NewResponseHandler()
-	paginationManager := pagination.NewManager(requestBuilder, customClient, responseHandler)
+	cache := newConditionalCache(store)
+	paginationManager := pagination.NewManager(requestBuilder, customClient, responseHandler, cache)
 
 	return &Client{
 		httpClient:        customClient,
 		requestBuilder:    requestBuilder,
 		responseHandler:   responseHandler,
 		paginationManager: paginationManager,
+		cache:             cache,
 	}
 }
 
-func (c *Client) GetCommits(ctx context.Context, owner, repoName, since, until string, commitsChan chan<- []Commit, errChan chan<- error) {
+// GetCommits streams commits page by page into commitsChan and reports completion on errChan.
+// When ctx carries WithConditionalRequests and the first page is unchanged, nothing is sent
+// to commitsChan and a nil error is reported.
+func (c *Client) GetCommits(ctx context.Context, owner, repoName string, opts CommitListOptions, commitsChan chan<- []Commit, errChan chan<- error) {
 	reqPath := fmt.Sprintf("/repos/%s/%s/commits", owner, repoName)
-	strategy := pagination.NewCommitStrategy(since, until)
+	strategy := pagination.NewCommitStrategy(opts.Branch, opts.Since, opts.Until)
 
 	processPage := func(data interface{}) error {
 		commits, ok := data.(*[]Commit)
@@ -42,8 +53,10 @@ func (c *Client) GetCommits(ctx context.Context, owner, repoName, since, until s
 			return errors.New("PROCESS_PAGE_ERROR", "unexpected type for commit data", fmt.Errorf("unexpected type %T", data), errors.Critical)
 		}
 
+		// out is reused for every page, so hand the consumer its own copy.
+		page := append([]Commit(nil), *commits...)
 		select {
-		case commitsChan <- *commits:
+		case commitsChan <- page:
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -53,15 +66,21 @@ func (c *Client) GetCommits(ctx context.Context, owner, repoName, since, until s
 	out := &[]Commit{}
 	fetchErr := c.paginationManager.FetchAllPages(ctx, reqPath, strategy, processPage, out)
 
-	// Signal completion
+	// Signal completion. The error is delivered whenever errChan has room, even once ctx is
+	// done, so that a consumer waiting for the outcome of a cancelled fetch still gets it.
 	select {
 	case errChan <- fetchErr:
-	case <-ctx.Done():
-		// Handle context cancellation, don't block
+	default:
+		select {
+		case errChan <- fetchErr:
+		case <-ctx.Done():
+			// Handle context cancellation, don't block
+		}
 	}
 }
 
 // GetRepository fetches repository details from GitHub.
+// When ctx carries WithConditionalRequests and the repository is unchanged, ErrNotModified is returned.
 func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*Repository, error) {
 	path := fmt.Sprintf("/repos/%s/%s", owner, repo)
 
@@ -69,6 +88,7 @@ func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*Reposi
 	if err != nil {
 		return nil, errors.New("BUILD_REQUEST_ERROR", "failed to build request", err, errors.Critical)
 	}
+	c.cache.Apply(ctx, req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -78,8 +98,147 @@ func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*Reposi
 
 	var repository Repository
 	if err := c.responseHandler.HandleResponse(resp, &repository); err != nil {
+		if stderrors.Is(err, httpclient.ErrNotModified) {
+			return nil, ErrNotModified
+		}
 		return nil, errors.New("HANDLE_RESPONSE_ERROR", "failed to handle response", err, errors.Critical)
 	}
+	c.cache.Store(ctx, req, resp)
 
 	return &repository, nil
 }
+
+// ListRepositories lists every repository of a user or organization. All repositories of an
+// organization visible to the client are listed, but only the repositories a user owns.
+func (c *Client) ListRepositories(ctx context.Context, owner string) ([]Repository, error) {
+	account, err := c.getAccount(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	reqPath := fmt.Sprintf("/users/%s/repos", owner)
+	strategy := pagination.NewRepositoryStrategy("owner")
+	if account.Type == AccountTypeOrganization {
+		reqPath = fmt.Sprintf("/orgs/%s/repos", owner)
+		strategy = pagination.NewRepositoryStrategy("all")
+	}
+
+	var repositories []Repository
+	processPage := func(data interface{}) error {
+		page, ok := data.(*[]Repository)
+		if !ok {
+			return errors.New("PROCESS_PAGE_ERROR", "unexpected type for repository data", fmt.Errorf("unexpected type %T", data), errors.Critical)
+		}
+		repositories = append(repositories, *page...)
+		return nil
+	}
+
+	out := &[]Repository{}
+	if err := c.paginationManager.FetchAllPages(ctx, reqPath, strategy, processPage, out); err != nil {
+		return nil, err
+	}
+	return repositories, nil
+}
+
+// ListBranches lists every branch of a repository.
+func (c *Client) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	reqPath := fmt.Sprintf("/repos/%s/%s/branches", owner, repo)
+
+	var branches []Branch
+	processPage := func(data interface{}) error {
+		page, ok := data.(*[]Branch)
+		if !ok {
+			return errors.New("PROCESS_PAGE_ERROR", "unexpected type for branch data", fmt.Errorf("unexpected type %T", data), errors.Critical)
+		}
+		branches = append(branches, *page...)
+		return nil
+	}
+
+	out := &[]Branch{}
+	if err := c.paginationManager.FetchAllPages(ctx, reqPath, pagination.NewBranchStrategy(), processPage, out); err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+// CompareCommits compares two commits, branches or tags. ErrNotFound is returned when either
+// of them does not exist.
+func (c *Client) CompareCommits(ctx context.Context, owner, repo, base, head string) (*Comparison, error) {
+	var comparison Comparison
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/%s/compare/%s...%s", owner, repo, base, head), &comparison); err != nil {
+		return nil, err
+	}
+	return &comparison, nil
+}
+
+// GetCommit fetches a commit with its line statistics and changed files, following the pages
+// of its files. ErrNotFound is returned when the commit does not exist.
+func (c *Client) GetCommit(ctx context.Context, owner, repo, sha string) (*CommitDetail, error) {
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s", owner, repo, sha)
+	params := pagination.CommitFilesQueryParams{Page: 1, PerPage: 300}
+
+	var commit CommitDetail
+	hasNext, err := c.getPage(ctx, path, params, &commit)
+	if err != nil {
+		return nil, err
+	}
+	for hasNext {
+		params.Page++
+		var page CommitDetail
+		if hasNext, err = c.getPage(ctx, path, params, &page); err != nil {
+			return nil, err
+		}
+		commit.Files = append(commit.Files, page.Files...)
+	}
+	commit.FilesTruncated = len(commit.Files) >= MaxCommitFiles
+	return &commit, nil
+}
+
+// GetBranch fetches a branch of a repository with its head commit. ErrNotFound is returned when
+// the branch does not exist.
+func (c *Client) GetBranch(ctx context.Context, owner, repo, branch string) (*Branch, error) {
+	var b Branch
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/%s/branches/%s", owner, repo, branch), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// getAccount fetches a GitHub user or organization.
+func (c *Client) getAccount(ctx context.Context, login string) (*Account, error) {
+	var account Account
+	if err := c.get(ctx, fmt.Sprintf("/users/%s", login), &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// get fetches a resource into out, reporting a 404 response as ErrNotFound. Rate limits are
+// waited out when ctx carries WithRateLimitWait.
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	_, err := c.getPage(ctx, path, nil, out)
+	return err
+}
+
+// getPage fetches a resource like get with the query parameters params, which may be nil, and
+// reports whether its Link header points to a next page.
+func (c *Client) getPage(ctx context.Context, path string, params interface{}, out interface{}) (bool, error) {
+	req, err := c.requestBuilder.BuildRequest(ctx, http.MethodGet, path, params, nil)
+	if err != nil {
+		return false, errors.New("BUILD_REQUEST_ERROR", "failed to build request", err, errors.Critical)
+	}
+
+	resp, err := pagination.Execute(ctx, c.httpClient, req, path)
+	if err != nil {
+		return false, errors.New("EXECUTE_REQUEST_ERROR", "failed to execute request", err, errors.Critical)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, ErrNotFound
+	}
+	if err := c.responseHandler.HandleResponse(resp, out); err != nil {
+		return false, errors.New("HANDLE_RESPONSE_ERROR", "failed to handle response", err, errors.Critical)
+	}
+	return c.paginationManager.HasNextPage(resp), nil
+}
@@ -0,0 +1,23 @@
+package pagination
+
+type BranchStrategy struct {
+	params BranchQueryParams
+}
+
+// NewBranchStrategy lists the branches of a repository.
+func NewBranchStrategy() *BranchStrategy {
+	return &BranchStrategy{
+		params: BranchQueryParams{
+			PerPage: 100,
+		},
+	}
+}
+
+func (s *BranchStrategy) InitializeParams() interface{} {
+	return s.params
+}
+
+func (s *BranchStrategy) UpdateParams(page int) (interface{}, error) {
+	s.params.Page = page
+	return s.params, nil
+}
@@ -4,11 +4,14 @@ type CommitStrategy struct {
 	params CommitQueryParams
 }
 
-func NewCommitStrategy(since, until string) *CommitStrategy {
+// NewCommitStrategy lists commits of sha, a branch or commit, between since and until. An empty
+// sha lists the default branch.
+func NewCommitStrategy(sha, since, until string) *CommitStrategy {
 	return &CommitStrategy{
 		params: CommitQueryParams{
 			Since:   since,
 			Until:   until,
+			Sha:     sha,
 			PerPage: 100,
 		},
 	}
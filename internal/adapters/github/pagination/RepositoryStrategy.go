@@ -0,0 +1,25 @@
+package pagination
+
+type RepositoryStrategy struct {
+	params RepositoryQueryParams
+}
+
+// NewRepositoryStrategy lists the repositories of an owner of the given type, such as "all"
+// for an organization or "owner" for a user.
+func NewRepositoryStrategy(repoType string) *RepositoryStrategy {
+	return &RepositoryStrategy{
+		params: RepositoryQueryParams{
+			Type:    repoType,
+			PerPage: 100,
+		},
+	}
+}
+
+func (s *RepositoryStrategy) InitializeParams() interface{} {
+	return s.params
+}
+
+func (s *RepositoryStrategy) UpdateParams(page int) (interface{}, error) {
+	s.params.Page = page
+	return s.params, nil
+}
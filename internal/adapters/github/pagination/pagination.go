@@ -2,29 +2,64 @@ package pagination
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"github.com/olusolaa/github-monitor/pkg/errors"
 	"github.com/olusolaa/github-monitor/pkg/httpclient"
+	"github.com/olusolaa/github-monitor/pkg/logger"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// minRateLimitWait keeps a retry from spinning when the reported reset time has already passed.
+const minRateLimitWait = time.Second
+
+type rateLimitWaitKey struct{}
+
+// WithRateLimitWait marks ctx so that FetchAllPages and Execute wait until a rate limit resets
+// and then retry the same request, instead of failing. Cancelling ctx aborts the wait.
+func WithRateLimitWait(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rateLimitWaitKey{}, true)
+}
+
+func rateLimitWaitEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(rateLimitWaitKey{}).(bool)
+	return enabled
+}
+
+// retryAtError is implemented by errors that report when a request may be retried.
+type retryAtError interface {
+	RetryAt() time.Time
+}
+
+// Validator applies and records HTTP cache validators for conditional requests.
+type Validator interface {
+	Apply(ctx context.Context, req *http.Request)
+	Store(ctx context.Context, req *http.Request, resp *http.Response)
+}
+
 // Manager manages paginated API requests.
 type Manager struct {
 	requestBuilder  *httpclient.RequestBuilder
 	requestExecutor *httpclient.Client
 	responseHandler *httpclient.ResponseHandler
+	validator       Validator
 }
 
 // NewManager creates a new instance of PaginationManager.
-func NewManager(rb *httpclient.RequestBuilder, re *httpclient.Client, rh *httpclient.ResponseHandler) *Manager {
+// The validator, if not nil, is used to make the first page request conditional.
+func NewManager(rb *httpclient.RequestBuilder, re *httpclient.Client, rh *httpclient.ResponseHandler, validator Validator) *Manager {
 	return &Manager{
 		requestBuilder:  rb,
 		requestExecutor: re,
 		responseHandler: rh,
+		validator:       validator,
 	}
 }
 
+// FetchAllPages walks every page of path, handing each decoded page to processPage.
+// If the first page comes back 304 Not Modified, nothing is processed and nil is returned.
 func (pm *Manager) FetchAllPages(
 	ctx context.Context,
 	path string,
@@ -45,8 +80,11 @@ func (pm *Manager) FetchAllPages(
 			fetchErr = errors.New("BUILD_REQUEST_ERROR", fmt.Sprintf("failed to build request for page %d", page), err, errors.Critical)
 			break
 		}
+		if page == 1 && pm.validator != nil {
+			pm.validator.Apply(ctx, req)
+		}
 
-		resp, err := pm.requestExecutor.Do(req)
+		resp, err := pm.execute(ctx, req, page)
 		if err != nil {
 			fetchErr = errors.New("REQUEST_EXECUTION_ERROR", fmt.Sprintf("failed to get data for page %d", page), err, errors.Critical)
 			break
@@ -54,6 +92,9 @@ func (pm *Manager) FetchAllPages(
 		defer resp.Body.Close()
 
 		if err = pm.responseHandler.HandleResponse(resp, out); err != nil {
+			if page == 1 && stderrors.Is(err, httpclient.ErrNotModified) {
+				return nil
+			}
 			fetchErr = errors.New("RESPONSE_HANDLING_ERROR", fmt.Sprintf("failed to process response for page %d", page), err, errors.Critical)
 			break
 		}
@@ -62,6 +103,9 @@ func (pm *Manager) FetchAllPages(
 			fetchErr = errors.New("PROCESS_PAGE_ERROR", fmt.Sprintf("error processing data for page %d", page), err, errors.Critical)
 			break
 		}
+		if page == 1 && pm.validator != nil {
+			pm.validator.Store(ctx, req, resp)
+		}
 
 		// Check if there are more pages
 		if !pm.HasNextPage(resp) {
@@ -80,6 +124,41 @@ func (pm *Manager) FetchAllPages(
 	return fetchErr
 }
 
+// execute sends req, waiting out rate limit errors when ctx carries WithRateLimitWait.
+func (pm *Manager) execute(ctx context.Context, req *http.Request, page int) (*http.Response, error) {
+	return Execute(ctx, pm.requestExecutor, req, fmt.Sprintf("page %d", page))
+}
+
+// Execute sends req with executor, waiting out rate limit errors when ctx carries
+// WithRateLimitWait. what describes the request in log messages.
+func Execute(ctx context.Context, executor *httpclient.Client, req *http.Request, what string) (*http.Response, error) {
+	for {
+		resp, err := executor.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+
+		var rateErr retryAtError
+		if !rateLimitWaitEnabled(ctx) || !stderrors.As(err, &rateErr) {
+			return nil, err
+		}
+
+		wait := time.Until(rateErr.RetryAt())
+		if wait < minRateLimitWait {
+			wait = minRateLimitWait
+		}
+		logger.LogWarning(fmt.Sprintf("Rate limited while fetching %s, resuming in %s", what, wait.Round(time.Second)))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
 // HasNextPage checks if there is a next page based on the Link header.
 func (pm *Manager) HasNextPage(resp *http.Response) bool {
 	linkHeader := resp.Header.Get("Link")
@@ -4,6 +4,26 @@ package pagination
 type CommitQueryParams struct {
 	Since   string `url:"since"`
 	Until   string `url:"until"`
+	Sha     string `url:"sha,omitempty"`
 	Page    int    `url:"page"`
 	PerPage int    `url:"per_page"`
 }
+
+// RepositoryQueryParams contains query parameters for listing the repositories of an owner
+type RepositoryQueryParams struct {
+	Type    string `url:"type,omitempty"`
+	Page    int    `url:"page"`
+	PerPage int    `url:"per_page"`
+}
+
+// CommitFilesQueryParams contains query parameters for listing the files changed by a commit
+type CommitFilesQueryParams struct {
+	Page    int `url:"page"`
+	PerPage int `url:"per_page"`
+}
+
+// BranchQueryParams contains query parameters for listing the branches of a repository
+type BranchQueryParams struct {
+	Page    int `url:"page"`
+	PerPage int `url:"per_page"`
+}
@@ -1,14 +1,29 @@
 package github
 
 import (
+	"bytes"
+	"context"
+	"github.com/olusolaa/github-monitor/internal/adapters/github/pagination"
 	"github.com/olusolaa/github-monitor/pkg/errors"
 	"github.com/olusolaa/github-monitor/pkg/httpclient"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
+// secondaryRateLimitBackoff is how long to wait after a secondary rate limit response that
+// carries no Retry-After header, as recommended by GitHub.
+const secondaryRateLimitBackoff = time.Minute
+
+// WithRateLimitWait marks ctx so that fetches wait for rate limits to reset and continue from
+// the same request instead of failing. Use it for background work only.
+func WithRateLimitWait(ctx context.Context) context.Context {
+	return pagination.WithRateLimitWait(ctx)
+}
+
 // RateLimiter manages rate limiting based on GitHub's rate limit headers.
 type RateLimiter struct {
 	mu        sync.Mutex
@@ -38,6 +53,10 @@ func (rl *RateLimiter) RateLimitMiddleware(req *http.Request, next httpclient.HT
 	}
 
 	rl.updateRateLimit(resp)
+	if rateErr := rateLimitError(resp, parseRateLimitHeaders(resp), time.Now()); rateErr != nil {
+		resp.Body.Close()
+		return nil, rateErr
+	}
 	return resp, nil
 }
 
@@ -46,17 +65,81 @@ func (rl *RateLimiter) updateRateLimit(resp *http.Response) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
+	state := parseRateLimitHeaders(resp)
+	if state.Remaining >= 0 {
+		rl.remaining = state.Remaining
+	}
+	if !state.Reset.IsZero() {
+		rl.reset = state.Reset
+	}
+}
+
+// rateLimitState is the rate limit information reported in a GitHub API response.
+// Limit and Remaining are -1 when the corresponding header is absent.
+type rateLimitState struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// parseRateLimitHeaders reads the X-RateLimit-* headers of resp.
+func parseRateLimitHeaders(resp *http.Response) rateLimitState {
+	state := rateLimitState{Limit: -1, Remaining: -1}
+
+	if limit := resp.Header.Get("X-RateLimit-Limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			state.Limit = l
+		}
+	}
+
 	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
 		if r, err := strconv.Atoi(remaining); err == nil {
-			rl.remaining = r
+			state.Remaining = r
 		}
 	}
 
 	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
 		if r, err := strconv.ParseInt(reset, 10, 64); err == nil {
-			rl.reset = time.Unix(r, 0)
+			state.Reset = time.Unix(r, 0)
+		}
+	}
+	return state
+}
+
+// rateLimitError reports whether resp was rejected by the primary or the secondary rate limit,
+// and when the request may be retried. It returns nil for any other response.
+func rateLimitError(resp *http.Response, state rateLimitState, now time.Time) *RateLimitExceededError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			wait := time.Duration(seconds) * time.Second
+			return &RateLimitExceededError{ResetTime: now.Add(wait), RetryAfter: wait}
 		}
 	}
+
+	if state.Remaining == 0 && !state.Reset.IsZero() {
+		return &RateLimitExceededError{ResetTime: state.Reset, RetryAfter: state.Reset.Sub(now)}
+	}
+
+	if isSecondaryRateLimit(resp) {
+		return &RateLimitExceededError{ResetTime: now.Add(secondaryRateLimitBackoff), RetryAfter: secondaryRateLimitBackoff}
+	}
+	return nil
+}
+
+// isSecondaryRateLimit checks the body of a 403/429 response for GitHub's secondary rate limit
+// message. The body is restored so the response can still be read by the caller.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
 }
 
 // RateLimitExceededError represents an error when the rate limit is exceeded.
@@ -68,3 +151,8 @@ type RateLimitExceededError struct {
 func (e *RateLimitExceededError) Error() string {
 	return "rate limit exceeded, retry after " + e.RetryAfter.String()
 }
+
+// RetryAt returns the time at which the request may be retried.
+func (e *RateLimitExceededError) RetryAt() time.Time {
+	return e.ResetTime
+}
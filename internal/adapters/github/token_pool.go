@@ -0,0 +1,206 @@
+package github
+
+import (
+	stderrors "errors"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/httpclient"
+)
+
+// pooledToken tracks the rate limit budget of a single token. Remaining is -1 until
+// GitHub has reported it.
+type pooledToken struct {
+	token     string
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// budget returns the number of requests the token can still make right now.
+func (t *pooledToken) budget(now time.Time) int {
+	if t.remaining < 0 || (!t.reset.IsZero() && now.After(t.reset)) {
+		return math.MaxInt32
+	}
+	return t.remaining
+}
+
+// ErrNoTokens is returned by NewTokenPool when it is given no token.
+var ErrNoTokens = stderrors.New("no GitHub token configured")
+
+// TokenStatus is the externally visible state of a pooled token. The token itself is masked.
+type TokenStatus struct {
+	Token     string     `json:"token"`
+	Limit     int        `json:"limit"`
+	Remaining int        `json:"remaining"`
+	Reset     *time.Time `json:"reset,omitempty"`
+	Exhausted bool       `json:"exhausted"`
+}
+
+// TokenPool authenticates requests with the token that has the most remaining rate limit
+// budget and switches to another token when GitHub reports one as exhausted.
+type TokenPool struct {
+	mu     sync.Mutex
+	tokens []*pooledToken
+	now    func() time.Time
+}
+
+// NewTokenPool creates a pool over the given tokens. Empty entries are ignored, and ErrNoTokens
+// is returned when no token is left.
+func NewTokenPool(tokens []string) (*TokenPool, error) {
+	pool := &TokenPool{now: time.Now}
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		pool.tokens = append(pool.tokens, &pooledToken{token: token, limit: -1, remaining: -1})
+	}
+	if len(pool.tokens) == 0 {
+		return nil, ErrNoTokens
+	}
+	return pool, nil
+}
+
+// Middleware authenticates the request with the best available token, retrying with the
+// next one when the chosen token turns out to be exhausted. Secondary rate limit responses
+// are reported as RateLimitExceededError without rotating.
+func (p *TokenPool) Middleware(req *http.Request, next httpclient.HTTPClient) (*http.Response, error) {
+	tried := make(map[*pooledToken]bool)
+	for {
+		token, err := p.acquire(tried)
+		if err != nil {
+			return nil, err
+		}
+		tried[token] = true
+
+		attempt := req
+		if len(tried) > 1 {
+			if attempt, err = cloneRequest(req); err != nil {
+				return nil, errors.New("HTTP_REQUEST_ERROR", "failed to retry request with another token", err, errors.Critical)
+			}
+		}
+		attempt.Header.Set("Authorization", "Bearer "+token.token)
+
+		resp, err := next.Do(attempt)
+		if err != nil {
+			return nil, errors.New("HTTP_REQUEST_ERROR", "failed to execute request", err, errors.Critical)
+		}
+
+		state := parseRateLimitHeaders(resp)
+		p.update(token, state)
+		if isRateLimitExhausted(resp, state) {
+			resp.Body.Close()
+			continue
+		}
+		if rateErr := rateLimitError(resp, state, p.now()); rateErr != nil {
+			resp.Body.Close()
+			return nil, rateErr
+		}
+		return resp, nil
+	}
+}
+
+// Status reports the state of every token in the pool.
+func (p *TokenPool) Status() []TokenStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	statuses := make([]TokenStatus, len(p.tokens))
+	for i, t := range p.tokens {
+		statuses[i] = TokenStatus{
+			Token:     maskToken(t.token),
+			Limit:     t.limit,
+			Remaining: t.remaining,
+			Exhausted: t.budget(now) == 0,
+		}
+		if !t.reset.IsZero() {
+			reset := t.reset
+			statuses[i].Reset = &reset
+		}
+	}
+	return statuses
+}
+
+// acquire picks the token with the most remaining budget that has not been tried yet and
+// reserves one request from it. When every token is exhausted it reports the earliest reset.
+func (p *TokenPool) acquire(tried map[*pooledToken]bool) (*pooledToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var best *pooledToken
+	var earliestReset time.Time
+	for _, t := range p.tokens {
+		if tried[t] {
+			continue
+		}
+		if t.budget(now) == 0 {
+			if earliestReset.IsZero() || t.reset.Before(earliestReset) {
+				earliestReset = t.reset
+			}
+			continue
+		}
+		if best == nil || t.budget(now) > best.budget(now) {
+			best = t
+		}
+	}
+
+	if best == nil {
+		if earliestReset.IsZero() {
+			earliestReset = now
+		}
+		return nil, &RateLimitExceededError{ResetTime: earliestReset, RetryAfter: earliestReset.Sub(now)}
+	}
+
+	if best.remaining > 0 {
+		best.remaining--
+	}
+	return best, nil
+}
+
+func (p *TokenPool) update(t *pooledToken, state rateLimitState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if state.Limit >= 0 {
+		t.limit = state.Limit
+	}
+	if state.Remaining >= 0 {
+		t.remaining = state.Remaining
+	}
+	if !state.Reset.IsZero() {
+		t.reset = state.Reset
+	}
+}
+
+// isRateLimitExhausted reports whether resp was rejected because the primary rate limit ran out.
+func isRateLimitExhausted(resp *http.Response, state rateLimitState) bool {
+	return (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) && state.Remaining == 0
+}
+
+// cloneRequest copies req so it can be sent again, rewinding its body when there is one.
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// maskToken hides all but the last four characters of a token.
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return strings.Repeat("*", len(token)-4) + token[len(token)-4:]
+}
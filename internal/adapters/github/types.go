@@ -2,17 +2,22 @@ package github
 
 import "time"
 
+// CommitListOptions selects the commits returned by Client.GetCommits.
+type CommitListOptions struct {
+	Since string
+	Until string
+	// Branch lists the commits of a branch instead of the default one.
+	Branch string
+}
+
 type Commit struct {
 	Sha    string `json:"sha"`
 	NodeId string `json:"node_id"`
 	Commit struct {
-		Committer struct {
-			Name  string    `json:"name"`
-			Email string    `json:"email"`
-			Date  time.Time `json:"date"`
-		} `json:"committer"`
-		Message string `json:"message"`
-		Tree    struct {
+		Author    GitIdentity `json:"author"`
+		Committer GitIdentity `json:"committer"`
+		Message   string      `json:"message"`
+		Tree      struct {
 			Sha string `json:"sha"`
 			Url string `json:"url"`
 		} `json:"tree"`
@@ -25,6 +30,27 @@ type Commit struct {
 			Payload   interface{} `json:"payload"`
 		} `json:"verification"`
 	} `json:"commit"`
+	HtmlUrl string `json:"html_url"`
+	// Author and Committer are the GitHub accounts of the git identities, nil when GitHub
+	// could not match an identity to an account.
+	Author    *User `json:"author"`
+	Committer *User `json:"committer"`
+	Parents   []struct {
+		Sha string `json:"sha"`
+	} `json:"parents"`
+}
+
+// GitIdentity is the author or committer recorded in a git commit.
+type GitIdentity struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// User is a GitHub account.
+type User struct {
+	Login string `json:"login"`
+	ID    int64  `json:"id"`
 }
 
 type Repository struct {
@@ -40,4 +66,71 @@ type Repository struct {
 	WatchersCount   int       `db:"watchers_count" json:"watchers_count"`
 	CreatedAt       time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Archived        bool      `db:"-" json:"archived"`
+	Fork            bool      `db:"-" json:"fork"`
+	DefaultBranch   string    `db:"-" json:"default_branch"`
 }
+
+// MaxCommitFiles is how many files of a commit GitHub lists at most.
+const MaxCommitFiles = 3000
+
+// CommitDetail is a single commit with its line statistics and the files it changed.
+// FilesTruncated is set when the commit changes more files than GitHub lists.
+type CommitDetail struct {
+	Commit
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+		Total     int `json:"total"`
+	} `json:"stats"`
+	Files          []CommitFile `json:"files"`
+	FilesTruncated bool         `json:"-"`
+}
+
+// CommitFile is a file changed by a commit.
+type CommitFile struct {
+	Filename         string `json:"filename"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Changes          int    `json:"changes"`
+	PreviousFilename string `json:"previous_filename"`
+}
+
+// Branch is a branch of a repository.
+type Branch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		Sha string `json:"sha"`
+	} `json:"commit"`
+}
+
+// Comparison is how the head of a comparison relates to its base.
+type Comparison struct {
+	Status          string `json:"status"`
+	AheadBy         int    `json:"ahead_by"`
+	BehindBy        int    `json:"behind_by"`
+	MergeBaseCommit Commit `json:"merge_base_commit"`
+}
+
+// Comparison statuses.
+const (
+	ComparisonIdentical = "identical"
+	ComparisonAhead     = "ahead"
+	ComparisonBehind    = "behind"
+	ComparisonDiverged  = "diverged"
+)
+
+// HeadContainsBase reports whether the base of the comparison is reachable from its head.
+func (c *Comparison) HeadContainsBase() bool {
+	return c.Status == ComparisonIdentical || c.Status == ComparisonAhead
+}
+
+// Account is a GitHub user or organization.
+type Account struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// AccountTypeOrganization is the Type of an Account that is an organization.
+const AccountTypeOrganization = "Organization"
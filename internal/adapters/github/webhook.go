@@ -0,0 +1,109 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Webhook event names sent in the X-GitHub-Event header.
+const (
+	EventPing       = "ping"
+	EventPush       = "push"
+	EventRepository = "repository"
+)
+
+// VerifySignature checks an X-Hub-Signature-256 header value against the HMAC-SHA256 of body.
+func VerifySignature(secret, signature string, body []byte) bool {
+	if secret == "" || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// WebhookOwner is the account owning a repository in webhook payloads.
+type WebhookOwner struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+// PushRepository is the repository object sent with push events. Its timestamps are
+// Unix seconds rather than RFC 3339 strings, so only the fields we need are decoded.
+type PushRepository struct {
+	Name          string       `json:"name"`
+	FullName      string       `json:"full_name"`
+	Owner         WebhookOwner `json:"owner"`
+	DefaultBranch string       `json:"default_branch"`
+	GitCommitsURL string       `json:"git_commits_url"`
+}
+
+// PushCommitIdentity is the author or committer of a pushed commit.
+type PushCommitIdentity struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// PushCommit is a commit listed in a push event.
+type PushCommit struct {
+	ID        string             `json:"id"`
+	TreeID    string             `json:"tree_id"`
+	Distinct  bool               `json:"distinct"`
+	Message   string             `json:"message"`
+	Timestamp time.Time          `json:"timestamp"`
+	URL       string             `json:"url"`
+	Author    PushCommitIdentity `json:"author"`
+	Committer PushCommitIdentity `json:"committer"`
+}
+
+// PushEvent is the payload of a push webhook.
+type PushEvent struct {
+	Ref        string         `json:"ref"`
+	Before     string         `json:"before"`
+	After      string         `json:"after"`
+	Created    bool           `json:"created"`
+	Deleted    bool           `json:"deleted"`
+	Forced     bool           `json:"forced"`
+	Commits    []PushCommit   `json:"commits"`
+	Repository PushRepository `json:"repository"`
+}
+
+// RepositoryEvent is the payload of a repository webhook. Changes carries the former name of a
+// renamed repository.
+type RepositoryEvent struct {
+	Action     string `json:"action"`
+	Repository struct {
+		Repository
+		Owner WebhookOwner `json:"owner"`
+	} `json:"repository"`
+	Changes struct {
+		Repository struct {
+			Name struct {
+				From string `json:"from"`
+			} `json:"name"`
+		} `json:"repository"`
+	} `json:"changes"`
+}
+
+// OwnerLogin returns the login of the repository owner, falling back to its name.
+func (o WebhookOwner) OwnerLogin() string {
+	if o.Login != "" {
+		return o.Login
+	}
+	return o.Name
+}
+
+// CommitAPIURL returns the git commit API URL for sha, matching the URL the REST API reports.
+func (r PushRepository) CommitAPIURL(sha string) string {
+	return strings.Replace(r.GitCommitsURL, "{/sha}", "/"+sha, 1)
+}
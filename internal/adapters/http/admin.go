@@ -0,0 +1,30 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// RegisterAdminRoutes registers operational endpoints. They require the X-API-Key header.
+func RegisterAdminRoutes(r chi.Router, tokenPool *github.TokenPool, contributorService services.ContributorService) {
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(APIKeyAuthMiddleware)
+		if tokenPool != nil {
+			r.Get("/rate-limits", getRateLimits(tokenPool))
+		}
+		registerContributorRoutes(r, contributorService)
+	})
+}
+
+func getRateLimits(tokenPool *github.TokenPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger.LogInfo("Token pool status fetched")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tokenPool.Status())
+	}
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+)
+
+// RegisterAuthorRoutes registers the endpoints ranking authors across repositories.
+func RegisterAuthorRoutes(r chi.Router, commitService services.CommitService) {
+	r.Get("/api/authors/top", getTopAuthors(commitService))
+}
+
+// getTopAuthors ranks the authors of every monitored repository by commit count, or of those of
+// the owner and label query parameters, each repeatable or comma separated. It takes the since,
+// until, exclude_bots, limit and cursor parameters of the top authors of a repository.
+func getTopAuthors(commitService services.CommitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, ok := parseAuthorFilter(w, r)
+		if !ok {
+			return
+		}
+		filter.Owners = queryValues(r, "owner")
+		filter.Labels = queryValues(r, "label")
+
+		writeTopAuthors(w, r, commitService, "", "", filter)
+	}
+}
+
+// queryValues returns the non-empty values of a query parameter given once or more, each a comma
+// separated list.
+func queryValues(r *http.Request, parameter string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[parameter] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
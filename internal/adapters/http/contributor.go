@@ -0,0 +1,244 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// registerContributorRoutes registers the endpoints managing the identities commit authors are
+// aggregated by, under the admin routes.
+func registerContributorRoutes(r chi.Router, contributorService services.ContributorService) {
+	r.Get("/contributors", listContributors(contributorService))
+	r.Post("/contributors", createContributor(contributorService))
+	r.Post("/contributors/mailmap", importMailmap(contributorService))
+	r.Get("/contributors/{id}", getContributor(contributorService))
+	r.Put("/contributors/{id}", updateContributor(contributorService))
+	r.Delete("/contributors/{id}", deleteContributor(contributorService))
+	r.Post("/contributors/{id}/merge", mergeContributors(contributorService))
+	r.Post("/contributors/{id}/aliases", addContributorAlias(contributorService))
+	r.Delete("/contributors/{id}/aliases/{aliasID}", deleteContributorAlias(contributorService))
+}
+
+func listContributors(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		contributors, err := contributorService.ListContributors(r.Context())
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Contributors fetched")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(contributors)
+	}
+}
+
+func getContributor(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseContributorID(w, r, "id")
+		if !ok {
+			return
+		}
+
+		contributor, err := contributorService.GetContributor(r.Context(), id)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(contributor)
+	}
+}
+
+// createContributor creates a contributor from a body such as
+// {"name": "Jane Doe", "email": "jane@example.com", "aliases": [{"email": "jane@home.example"}]}.
+func createContributor(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var contributor domain.Contributor
+		if !decodeContributorBody(w, r, &contributor) {
+			return
+		}
+
+		if err := contributorService.CreateContributor(r.Context(), &contributor); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(contributor)
+	}
+}
+
+// updateContributor sets the name, email and login of a contributor from a body such as
+// {"name": "Jane Doe", "email": "jane@example.com", "login": "jane"}.
+func updateContributor(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseContributorID(w, r, "id")
+		if !ok {
+			return
+		}
+		var contributor domain.Contributor
+		if !decodeContributorBody(w, r, &contributor) {
+			return
+		}
+		contributor.ID = id
+
+		if err := contributorService.UpdateContributor(r.Context(), &contributor); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(contributor)
+	}
+}
+
+func deleteContributor(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseContributorID(w, r, "id")
+		if !ok {
+			return
+		}
+
+		if err := contributorService.DeleteContributor(r.Context(), id); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Contributor deleted successfully"})
+	}
+}
+
+// mergeContributors merges the contributor of a body such as {"contributor_id": 7} into the
+// contributor of the path.
+func mergeContributors(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseContributorID(w, r, "id")
+		if !ok {
+			return
+		}
+		var req struct {
+			ContributorID int64 `json:"contributor_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errMsg := "Invalid request body"
+			logger.LogWarning(errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+
+		contributor, err := contributorService.MergeContributors(r.Context(), id, req.ContributorID)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(contributor)
+	}
+}
+
+// addContributorAlias gives the contributor of the path an alias from a body such as
+// {"name": "Jane", "email": "jane@home.example"} or {"login": "jane-doe"}.
+func addContributorAlias(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseContributorID(w, r, "id")
+		if !ok {
+			return
+		}
+		var alias domain.ContributorAlias
+		if err := json.NewDecoder(r.Body).Decode(&alias); err != nil {
+			errMsg := "Invalid request body"
+			logger.LogWarning(errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		alias.ContributorID = id
+
+		if err := contributorService.AddAlias(r.Context(), &alias); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(alias)
+	}
+}
+
+func deleteContributorAlias(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parseContributorID(w, r, "id")
+		if !ok {
+			return
+		}
+		aliasID, ok := parseContributorID(w, r, "aliasID")
+		if !ok {
+			return
+		}
+
+		if err := contributorService.DeleteAlias(r.Context(), id, aliasID); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Contributor alias deleted successfully"})
+	}
+}
+
+// importMailmap imports a .mailmap file sent as the request body.
+func importMailmap(contributorService services.ContributorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		imported, err := contributorService.ImportMailmap(r.Context(), r.Body)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Mailmap imported successfully", "entries": imported})
+	}
+}
+
+// decodeContributorBody decodes a contributor from the request body.
+func decodeContributorBody(w http.ResponseWriter, r *http.Request, contributor *domain.Contributor) bool {
+	if err := json.NewDecoder(r.Body).Decode(contributor); err != nil {
+		errMsg := "Invalid request body"
+		logger.LogWarning(errMsg)
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// parseContributorID reads a contributor or alias ID from the URL parameter param.
+func parseContributorID(w http.ResponseWriter, r *http.Request, param string) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, param), 10, 64)
+	if err != nil {
+		errMsg := "Invalid " + param
+		logger.LogWarning(errMsg)
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
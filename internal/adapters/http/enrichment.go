@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// RegisterEnrichmentRoutes registers the endpoints for the line statistics and changed files
+// of commits.
+func RegisterEnrichmentRoutes(r chi.Router, enrichmentService services.EnrichmentService) {
+	r.Post("/api/repos/{owner}/{name}/enrich", enrichRepository(enrichmentService))
+	r.Get("/api/repos/{owner}/{name}/commits/{sha}/files", getCommitFiles(enrichmentService))
+}
+
+// enrichRepository backfills the line statistics and changed files of the commits of a
+// repository that were stored without them.
+func enrichRepository(enrichmentService services.EnrichmentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		owner := chi.URLParam(r, "owner")
+
+		queued, err := enrichmentService.RequestEnrichment(r.Context(), owner, name)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Enrichment triggered for repository: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Enrichment triggered successfully", "commits": queued})
+	}
+}
+
+func getCommitFiles(enrichmentService services.EnrichmentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		owner := chi.URLParam(r, "owner")
+		sha := chi.URLParam(r, "sha")
+
+		files, err := enrichmentService.GetCommitFiles(r.Context(), owner, name, sha)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Commit files fetched for commit: " + owner + "/" + name + "@" + sha)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(files)
+	}
+}
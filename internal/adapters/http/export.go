@@ -0,0 +1,62 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// exportContentTypes are the content types of the commit export formats.
+var exportContentTypes = map[string]string{
+	domain.ExportFormatCSV:    "text/csv; charset=utf-8",
+	domain.ExportFormatNDJSON: "application/x-ndjson",
+}
+
+// RegisterExportRoutes registers the endpoints exporting commits in bulk.
+func RegisterExportRoutes(r chi.Router, exportService services.ExportService) {
+	r.Get("/api/repos/{owner}/{name}/commits/export", exportCommits(exportService))
+}
+
+// exportCommits streams the commits of a repository matching the filters of the commits
+// endpoint as a CSV or NDJSON file, selected by the format query parameter.
+func exportCommits(exportService services.ExportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		owner := chi.URLParam(r, "owner")
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = domain.ExportFormatCSV
+		}
+		if err := domain.ValidateExportFormat(format); err != nil {
+			badQueryParameter(w, "format", err)
+			return
+		}
+
+		filter, ok := parseCommitFilter(w, r)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", exportContentTypes[format])
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-commits.%s"`, owner, name, format))
+
+		exported, err := exportService.ExportCommits(r.Context(), owner, name, filter, format, w)
+		if err != nil {
+			logger.LogError(err)
+			if exported == 0 {
+				// Nothing was streamed yet, so the failure can still be reported.
+				w.Header().Del("Content-Disposition")
+				errors.HandleError(w, err)
+			}
+			return
+		}
+
+		logger.LogInfo(fmt.Sprintf("Exported %d commits of repository: %s/%s", exported, owner, name))
+	}
+}
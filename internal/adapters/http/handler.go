@@ -2,14 +2,18 @@ package http
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"github.com/olusolaa/github-monitor/pkg/errors"
 	"github.com/olusolaa/github-monitor/pkg/logger"
 	"github.com/olusolaa/github-monitor/pkg/pagination"
+	"github.com/olusolaa/github-monitor/pkg/utils"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/internal/core/services"
 )
 
@@ -18,17 +22,81 @@ func RegisterRoutes(r chi.Router, repoService services.RepositoryService, commit
 		r.Get("/repos/{owner}/{repo}", getRepository(repoService))
 		r.Get("/repos/{owner}/{name}/commits", getCommits(commitService))
 		r.Get("/repos/{owner}/{name}/top-authors", getTopCommitAuthors(commitService))
+		r.Get("/repos/{owner}/{name}/history-rewrites", getHistoryRewrites(commitService))
+		r.Get("/repos/{owner}/{name}/stats/activity", getCommitActivity(commitService))
+		r.Get("/repos/{owner}/{name}/stats/punch-card", getPunchCard(commitService))
 		r.Post("/repos/{owner}/{name}/reset-collection", resetCollection(commitService))
-		r.Post("/repos/{owner}/{name}/monitor", monitorRepository(repoService))
+		r.Post("/repos/{owner}/{name}/monitor", monitorRepository(repoService, commitService))
+		r.Delete("/repos/{owner}/{name}", removeRepository(repoService))
+		r.Post("/repos/{owner}/{name}/pause", pauseRepository(repoService))
+		r.Post("/repos/{owner}/{name}/resume", resumeRepository(repoService))
+		r.Put("/repos/{owner}/{name}/schedule", setSchedule(repoService))
+		r.Put("/repos/{owner}/{name}/branches", setBranches(repoService))
+		r.Get("/jobs/{id}", getJob(commitService))
+		r.Delete("/jobs/{id}", cancelJob(commitService))
+		r.Post("/jobs/{id}/retry", retryJob(commitService))
 	})
 }
 
-func monitorRepository(repoService services.RepositoryService) http.HandlerFunc {
+// scheduleRequest is the body accepted by the schedule and monitor endpoints.
+type scheduleRequest struct {
+	Schedule string `json:"schedule"`
+}
+
+// decodeScheduleRequest reads an optional schedule request body and validates its schedule.
+func decodeScheduleRequest(w http.ResponseWriter, r *http.Request) (*scheduleRequest, bool) {
+	var req scheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !stderrors.Is(err, io.EOF) {
+		errMsg := "Invalid request body"
+		logger.LogWarning(errMsg)
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.Schedule != "" {
+		if _, _, err := utils.ParseSchedule(req.Schedule); err != nil {
+			logger.LogWarning(err.Error())
+			http.Error(w, "Invalid schedule: "+err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+func monitorRepository(repoService services.RepositoryService, commitService services.CommitService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		owner := chi.URLParam(r, "owner")
 		name := chi.URLParam(r, "name")
 
-		err := repoService.AddRepository(owner, name)
+		req, ok := decodeScheduleRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// The schedule was validated by decodeScheduleRequest, so it is known to apply before
+		// the repository is stored.
+		repository, err := repoService.FetchRepository(r.Context(), owner, name, nil)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+		if repository == nil {
+			errMsg := "Repository not found"
+			logger.LogWarning(errMsg + ": " + owner + "/" + name)
+			http.Error(w, errMsg, http.StatusNotFound)
+			return
+		}
+
+		if req.Schedule != "" {
+			if err := repoService.SetSchedule(r.Context(), repository.Owner, repository.Name, req.Schedule); err != nil {
+				logger.LogError(err)
+				errors.HandleError(w, err)
+				return
+			}
+		}
+
+		job, err := commitService.StartBackfill(r.Context(), repository.ID)
 		if err != nil {
 			logger.LogError(err)
 			errors.HandleError(w, err)
@@ -37,8 +105,123 @@ func monitorRepository(repoService services.RepositoryService) http.HandlerFunc
 
 		logger.LogInfo("Repository monitoring triggered for: " + owner + "/" + name)
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{"message": "Repository monitoring triggered successfully"})
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Repository monitoring triggered successfully", "job_id": job.ID})
+	}
+}
+
+func removeRepository(repoService services.RepositoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner := chi.URLParam(r, "owner")
+		name := chi.URLParam(r, "name")
+
+		purge := false
+		if purgeStr := r.URL.Query().Get("purge"); purgeStr != "" {
+			var err error
+			purge, err = strconv.ParseBool(purgeStr)
+			if err != nil {
+				errMsg := "Invalid purge value, must be a boolean"
+				logger.LogWarning(errMsg)
+				http.Error(w, errMsg, http.StatusBadRequest)
+				return
+			}
+		}
+
+		if err := repoService.RemoveRepository(r.Context(), owner, name, purge); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Repository monitoring stopped for: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Repository monitoring stopped successfully"})
+	}
+}
+
+func pauseRepository(repoService services.RepositoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner := chi.URLParam(r, "owner")
+		name := chi.URLParam(r, "name")
+
+		if err := repoService.PauseRepository(r.Context(), owner, name); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Repository monitoring paused for: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Repository monitoring paused successfully"})
+	}
+}
+
+func resumeRepository(repoService services.RepositoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner := chi.URLParam(r, "owner")
+		name := chi.URLParam(r, "name")
+
+		if err := repoService.ResumeRepository(r.Context(), owner, name); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Repository monitoring resumed for: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Repository monitoring resumed successfully"})
+	}
+}
+
+func setSchedule(repoService services.RepositoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner := chi.URLParam(r, "owner")
+		name := chi.URLParam(r, "name")
+
+		req, ok := decodeScheduleRequest(w, r)
+		if !ok {
+			return
+		}
+
+		if err := repoService.SetSchedule(r.Context(), owner, name, req.Schedule); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Repository schedule updated for: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Repository schedule updated successfully"})
+	}
+}
+
+// branchesRequest is the body accepted by the branches endpoint.
+type branchesRequest struct {
+	Branches []string `json:"branches"`
+}
+
+func setBranches(repoService services.RepositoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner := chi.URLParam(r, "owner")
+		name := chi.URLParam(r, "name")
+
+		var req branchesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errMsg := "Invalid request body"
+			logger.LogWarning(errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+
+		if err := repoService.SetBranches(r.Context(), owner, name, req.Branches); err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Repository branches updated for: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Repository branches updated successfully"})
 	}
 }
 
@@ -72,16 +255,36 @@ func getCommits(commitService services.CommitService) http.HandlerFunc {
 			return
 		}
 
-		commits, pg, err := commitService.GetCommitsByRepositoryName(r.Context(), owner, name, page, pageSize)
+		cursorParams, err := pagination.ParseCursorParams(r.URL.Query())
 		if err != nil {
-			logger.LogError(err)
-			errors.HandleError(w, err)
+			badQueryParameter(w, "cursor", err)
 			return
 		}
 
-		response := pagination.PagedResponse{
-			Pagination: pg,
-			Data:       commits,
+		filter, ok := parseCommitFilter(w, r)
+		if !ok {
+			return
+		}
+
+		var response pagination.PagedResponse
+		if cursorParams != nil {
+			commits, cursors, err := commitService.GetCommitsByCursor(r.Context(), owner, name, filter, cursorParams)
+			if err != nil {
+				logger.LogError(err)
+				errors.HandleError(w, err)
+				return
+			}
+			response = pagination.PagedResponse{Cursors: cursors, Data: commits}
+			setLinkHeader(w, pagination.CursorLinks(r.URL, cursors))
+		} else {
+			commits, pg, err := commitService.GetCommitsByRepositoryName(r.Context(), owner, name, filter, page, pageSize)
+			if err != nil {
+				logger.LogError(err)
+				errors.HandleError(w, err)
+				return
+			}
+			response = pagination.PagedResponse{Pagination: pg, Data: commits}
+			setLinkHeader(w, pagination.PageLinks(r.URL, pg))
 		}
 
 		logger.LogInfo("Commits fetched for repository: " + owner + "/" + name)
@@ -90,24 +293,111 @@ func getCommits(commitService services.CommitService) http.HandlerFunc {
 	}
 }
 
-func getTopCommitAuthors(commitService services.CommitService) http.HandlerFunc {
+func getHistoryRewrites(commitService services.CommitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		owner := chi.URLParam(r, "owner")
+
+		rewrites, err := commitService.GetHistoryRewrites(r.Context(), owner, name)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("History rewrites fetched for repository: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rewrites)
+	}
+}
+
+// getCommitActivity counts the commits of a repository per day, week or month, selected by the
+// interval query parameter, between since and until. by_author=true breaks the counts down by
+// author.
+func getCommitActivity(commitService services.CommitService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		name := chi.URLParam(r, "name")
 		owner := chi.URLParam(r, "owner")
-		limitStr := r.URL.Query().Get("limit")
 
-		limit := 10 // Default limit
-		var err error
-		if limitStr != "" {
-			limit, err = strconv.Atoi(limitStr)
+		since, until, ok := parseDateRange(w, r)
+		if !ok {
+			return
+		}
+		query := domain.ActivityQuery{Interval: r.URL.Query().Get("interval"), Since: since, Until: until}
+		if value := r.URL.Query().Get("by_author"); value != "" {
+			byAuthor, err := strconv.ParseBool(value)
 			if err != nil {
-				logger.LogError(err)
-				errors.HandleError(w, err)
+				badQueryParameter(w, "by_author", err)
 				return
 			}
+			query.ByAuthor = byAuthor
+		}
+
+		buckets, err := commitService.GetCommitActivity(r.Context(), owner, name, query)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Commit activity fetched for repository: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(buckets)
+	}
+}
+
+// getPunchCard counts the commits of a repository by weekday and hour between since and until.
+func getPunchCard(commitService services.CommitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		owner := chi.URLParam(r, "owner")
+
+		since, until, ok := parseDateRange(w, r)
+		if !ok {
+			return
 		}
 
-		authors, err := commitService.GetTopCommitAuthors(r.Context(), owner, name, limit)
+		entries, err := commitService.GetPunchCard(r.Context(), owner, name, since, until)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Punch card fetched for repository: " + owner + "/" + name)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+	}
+}
+
+// getTopCommitAuthors ranks the authors of a repository by commit count, optionally between
+// since and until and without bots when exclude_bots=true.
+func getTopCommitAuthors(commitService services.CommitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		owner := chi.URLParam(r, "owner")
+
+		filter, ok := parseAuthorFilter(w, r)
+		if !ok {
+			return
+		}
+		writeTopAuthors(w, r, commitService, owner, name, filter)
+	}
+}
+
+// writeTopAuthors writes the authors with the most commits matching filter in a repository, or
+// across repositories when owner and name are empty: the first limit (10 by default), or a page
+// of all of them when a cursor is given.
+func writeTopAuthors(w http.ResponseWriter, r *http.Request, commitService services.CommitService, owner, name string, filter domain.AuthorFilter) {
+	limitStr := r.URL.Query().Get("limit")
+
+	cursorParams, err := pagination.ParseCursorParams(r.URL.Query())
+	if err != nil {
+		badQueryParameter(w, "cursor", err)
+		return
+	}
+	if cursorParams != nil {
+		authors, cursors, err := commitService.GetTopCommitAuthorsByCursor(r.Context(), owner, name, filter, cursorParams)
 		if err != nil {
 			logger.LogError(err)
 			errors.HandleError(w, err)
@@ -115,9 +405,32 @@ func getTopCommitAuthors(commitService services.CommitService) http.HandlerFunc
 		}
 
 		logger.LogInfo("Top commit authors fetched for repository name: " + name)
+		setLinkHeader(w, pagination.CursorLinks(r.URL, cursors))
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(authors)
+		json.NewEncoder(w).Encode(pagination.PagedResponse{Cursors: cursors, Data: authors})
+		return
 	}
+
+	limit := 10 // Default limit
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+	}
+
+	authors, err := commitService.GetTopCommitAuthors(r.Context(), owner, name, filter, limit)
+	if err != nil {
+		logger.LogError(err)
+		errors.HandleError(w, err)
+		return
+	}
+
+	logger.LogInfo("Top commit authors fetched for repository name: " + name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(authors)
 }
 
 func resetCollection(commitService services.CommitService) http.HandlerFunc {
@@ -141,7 +454,7 @@ func resetCollection(commitService services.CommitService) http.HandlerFunc {
 			return
 		}
 
-		err = commitService.ResetCollection(r.Context(), owner, name, startTime)
+		job, err := commitService.ResetCollection(r.Context(), owner, name, startTime)
 		if err != nil {
 			logger.LogError(err)
 			errors.HandleError(w, err)
@@ -150,7 +463,164 @@ func resetCollection(commitService services.CommitService) http.HandlerFunc {
 
 		logger.LogInfo("Collection reset successfully for repository name: " + name)
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{"message": "Collection reset successfully"})
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Collection reset successfully", "job_id": job.ID})
+	}
+}
+
+func getJob(commitService services.CommitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID, ok := parseJobID(w, r)
+		if !ok {
+			return
+		}
+
+		job, err := commitService.GetJob(r.Context(), jobID)
+		if err != nil {
+			handleJobError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
+func cancelJob(commitService services.CommitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID, ok := parseJobID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := commitService.CancelJob(r.Context(), jobID); err != nil {
+			handleJobError(w, err)
+			return
+		}
+
+		logger.LogInfo("Backfill job cancellation requested: " + chi.URLParam(r, "id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"message": "Job cancellation requested"})
+	}
+}
+
+func retryJob(commitService services.CommitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID, ok := parseJobID(w, r)
+		if !ok {
+			return
+		}
+
+		job, err := commitService.RetryJob(r.Context(), jobID)
+		if err != nil {
+			handleJobError(w, err)
+			return
+		}
+
+		logger.LogInfo("Backfill job restarted: " + chi.URLParam(r, "id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
+// parseCommitFilter reads the commit filter from the query parameters branch, author, since,
+// until, q, path, merge and sort. Dates are RFC 3339 or YYYY-MM-DD, a date alone as until
+// covering the whole day.
+func parseCommitFilter(w http.ResponseWriter, r *http.Request) (domain.CommitFilter, bool) {
+	query := r.URL.Query()
+	filter := domain.CommitFilter{
+		Branch: query.Get("branch"),
+		Author: query.Get("author"),
+		Query:  query.Get("q"),
+		Path:   query.Get("path"),
+		Sort:   query.Get("sort"),
+	}
+
+	var ok bool
+	if filter.Since, filter.Until, ok = parseDateRange(w, r); !ok {
+		return filter, false
+	}
+	if value := query.Get("merge"); value != "" {
+		merge, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, badQueryParameter(w, "merge", err)
+		}
+		filter.Merge = &merge
+	}
+	return filter, true
+}
+
+// parseAuthorFilter reads the author leaderboard filter from the query parameters since, until
+// and exclude_bots.
+func parseAuthorFilter(w http.ResponseWriter, r *http.Request) (domain.AuthorFilter, bool) {
+	var filter domain.AuthorFilter
+	var ok bool
+	if filter.Since, filter.Until, ok = parseDateRange(w, r); !ok {
+		return filter, false
+	}
+	if value := r.URL.Query().Get("exclude_bots"); value != "" {
+		excludeBots, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, badQueryParameter(w, "exclude_bots", err)
+		}
+		filter.ExcludeBots = excludeBots
+	}
+	return filter, true
+}
+
+// parseDateRange reads the optional since and until query parameters, RFC 3339 times or
+// YYYY-MM-DD dates, a date alone as until covering the whole day.
+func parseDateRange(w http.ResponseWriter, r *http.Request) (*time.Time, *time.Time, bool) {
+	since, err := utils.ParseDate(r.URL.Query().Get("since"), false)
+	if err != nil {
+		return nil, nil, badQueryParameter(w, "since", err)
+	}
+	until, err := utils.ParseDate(r.URL.Query().Get("until"), true)
+	if err != nil {
+		return nil, nil, badQueryParameter(w, "until", err)
+	}
+	return since, until, true
+}
+
+// setLinkHeader sets the Link header of a paginated response, unless there are no links.
+func setLinkHeader(w http.ResponseWriter, links string) {
+	if links != "" {
+		w.Header().Set("Link", links)
+	}
+}
+
+// badQueryParameter responds that a query parameter is invalid.
+func badQueryParameter(w http.ResponseWriter, parameter string, err error) bool {
+	errMsg := "Invalid " + parameter + " query parameter"
+	logger.LogWarning(errMsg + ": " + err.Error())
+	http.Error(w, errMsg, http.StatusBadRequest)
+	return false
+}
+
+func parseJobID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	jobID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		errMsg := "Invalid job ID"
+		logger.LogWarning(errMsg)
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return 0, false
+	}
+	return jobID, true
+}
+
+// handleJobError maps job state errors to 404 and 409 responses.
+func handleJobError(w http.ResponseWriter, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrJobNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case stderrors.Is(err, services.ErrJobNotRunning),
+		stderrors.Is(err, services.ErrJobNotRetryable),
+		stderrors.Is(err, services.ErrBackfillInProgress):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.LogError(err)
+		errors.HandleError(w, err)
 	}
 }
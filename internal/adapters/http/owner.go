@@ -0,0 +1,47 @@
+package http
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// RegisterOwnerRoutes registers the endpoints monitoring every repository of an owner.
+func RegisterOwnerRoutes(r chi.Router, ownerService services.OwnerService) {
+	r.Post("/api/owners/{owner}/monitor", monitorOwner(ownerService))
+}
+
+// monitorOwner monitors the repositories of a user or organization selected by an optional
+// filter body such as {"include": ["service-*"], "languages": ["Go"], "include_forks": true}.
+func monitorOwner(ownerService services.OwnerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		owner := chi.URLParam(r, "owner")
+
+		var filter domain.RepositoryFilter
+		if err := json.NewDecoder(r.Body).Decode(&filter); err != nil && !stderrors.Is(err, io.EOF) {
+			errMsg := "Invalid request body"
+			logger.LogWarning(errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+
+		added, err := ownerService.MonitorOwner(r.Context(), owner, filter)
+		if err != nil {
+			logger.LogError(err)
+			errors.HandleError(w, err)
+			return
+		}
+
+		logger.LogInfo("Owner monitoring triggered for: " + owner)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Owner monitoring triggered successfully", "repositories": added})
+	}
+}
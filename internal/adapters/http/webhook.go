@@ -0,0 +1,64 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// maxWebhookPayloadSize matches the 25 MB cap GitHub places on webhook payloads.
+const maxWebhookPayloadSize = 25 << 20
+
+func RegisterWebhookRoutes(r chi.Router, webhookService services.WebhookService, secret string) {
+	r.Post("/webhooks/github", githubWebhook(webhookService, secret))
+}
+
+func githubWebhook(webhookService services.WebhookService, secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayloadSize))
+		if err != nil {
+			logger.LogError(err)
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		if !github.VerifySignature(secret, r.Header.Get("X-Hub-Signature-256"), body) {
+			logger.LogWarning("Rejected webhook delivery with invalid signature")
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		event := r.Header.Get("X-GitHub-Event")
+		deliveryID := r.Header.Get("X-GitHub-Delivery")
+		if event == "" || deliveryID == "" {
+			errMsg := "X-GitHub-Event and X-GitHub-Delivery headers are required"
+			logger.LogWarning(errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if event == github.EventPing {
+			json.NewEncoder(w).Encode(map[string]string{"message": "pong"})
+			return
+		}
+
+		processed, err := webhookService.HandleDelivery(r.Context(), deliveryID, event, body)
+		if err != nil {
+			errors.HandleError(w, err)
+			return
+		}
+
+		message := "Delivery processed"
+		if !processed {
+			message = "Delivery already processed"
+		}
+		json.NewEncoder(w).Encode(map[string]string{"message": message})
+	}
+}
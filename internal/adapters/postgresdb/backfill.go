@@ -0,0 +1,119 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"time"
+)
+
+type backfillJobRepository struct {
+	db *sqlx.DB
+}
+
+type BackfillJobRepository interface {
+	Create(ctx context.Context, job *domain.BackfillJob) error
+	SaveCheckpoint(ctx context.Context, jobID int64, branch string, completedBranches []string, lastPage, commitsSaved int, lastCommitDate *time.Time) error
+	UpdateStatus(ctx context.Context, jobID int64, status, errMsg string) error
+	FindByID(ctx context.Context, jobID int64) (*domain.BackfillJob, error)
+	FindUnfinished(ctx context.Context) ([]domain.BackfillJob, error)
+	FindUnfinishedByRepositoryID(ctx context.Context, repoID int64) (*domain.BackfillJob, error)
+	FindCompletedByRepositoryID(ctx context.Context, repoID int64) (*domain.BackfillJob, error)
+}
+
+func NewBackfillJobRepository(db *sqlx.DB) BackfillJobRepository {
+	return &backfillJobRepository{db: db}
+}
+
+const backfillJobColumns = `id, repository_id, since, until, branch, completed_branches, status, last_page, commits_saved, last_commit_date, error, created_at, updated_at`
+
+// Create inserts a new backfill job and sets its ID and timestamps.
+func (r backfillJobRepository) Create(ctx context.Context, job *domain.BackfillJob) error {
+	query := `
+        INSERT INTO backfill_jobs (repository_id, since, until, status)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, updated_at;
+    `
+	err := r.db.QueryRowContext(ctx, query, job.RepositoryID, job.Since, job.Until, job.Status).
+		Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create backfill job: %w", err)
+	}
+	return nil
+}
+
+// SaveCheckpoint records the progress of a job after a page of commits of branch has been saved,
+// or once the branch has been collected entirely and added to completedBranches.
+func (r backfillJobRepository) SaveCheckpoint(ctx context.Context, jobID int64, branch string, completedBranches []string, lastPage, commitsSaved int, lastCommitDate *time.Time) error {
+	query := `
+        UPDATE backfill_jobs
+        SET branch = $1, completed_branches = COALESCE($2, '{}'), last_page = $3, commits_saved = $4, last_commit_date = $5, updated_at = NOW()
+        WHERE id = $6;
+    `
+	if _, err := r.db.ExecContext(ctx, query, branch, pq.StringArray(completedBranches), lastPage, commitsSaved, lastCommitDate, jobID); err != nil {
+		return fmt.Errorf("failed to save backfill checkpoint: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus records the status of a job along with the error that ended it, if any.
+func (r backfillJobRepository) UpdateStatus(ctx context.Context, jobID int64, status, errMsg string) error {
+	query := `UPDATE backfill_jobs SET status = $1, error = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := r.db.ExecContext(ctx, query, status, errMsg, jobID); err != nil {
+		return fmt.Errorf("failed to update backfill job status: %w", err)
+	}
+	return nil
+}
+
+// FindByID retrieves a job by its ID.
+func (r backfillJobRepository) FindByID(ctx context.Context, jobID int64) (*domain.BackfillJob, error) {
+	query := `SELECT ` + backfillJobColumns + ` FROM backfill_jobs WHERE id = $1`
+	var job domain.BackfillJob
+	if err := r.db.GetContext(ctx, &job, query, jobID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find backfill job: %w", err)
+	}
+	return &job, nil
+}
+
+// FindUnfinished retrieves all jobs that were still running, oldest first.
+func (r backfillJobRepository) FindUnfinished(ctx context.Context) ([]domain.BackfillJob, error) {
+	query := `SELECT ` + backfillJobColumns + ` FROM backfill_jobs WHERE status = $1 ORDER BY id`
+	var jobs []domain.BackfillJob
+	if err := r.db.SelectContext(ctx, &jobs, query, domain.BackfillStatusRunning); err != nil {
+		return nil, fmt.Errorf("failed to find unfinished backfill jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// FindUnfinishedByRepositoryID retrieves the most recent running job for a repository.
+func (r backfillJobRepository) FindUnfinishedByRepositoryID(ctx context.Context, repoID int64) (*domain.BackfillJob, error) {
+	query := `SELECT ` + backfillJobColumns + ` FROM backfill_jobs WHERE repository_id = $1 AND status = $2 ORDER BY id DESC LIMIT 1`
+	var job domain.BackfillJob
+	if err := r.db.GetContext(ctx, &job, query, repoID, domain.BackfillStatusRunning); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find unfinished backfill job: %w", err)
+	}
+	return &job, nil
+}
+
+// FindCompletedByRepositoryID retrieves the most recent completed job for a repository.
+func (r backfillJobRepository) FindCompletedByRepositoryID(ctx context.Context, repoID int64) (*domain.BackfillJob, error) {
+	query := `SELECT ` + backfillJobColumns + ` FROM backfill_jobs WHERE repository_id = $1 AND status = $2 ORDER BY id DESC LIMIT 1`
+	var job domain.BackfillJob
+	if err := r.db.GetContext(ctx, &job, query, repoID, domain.BackfillStatusCompleted); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find completed backfill job: %w", err)
+	}
+	return &job, nil
+}
@@ -6,10 +6,89 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/pkg/pagination"
+	"strings"
+	"time"
 )
 
+// commitColumns lists the columns of a commit row read through the alias c.
+const commitColumns = `c.id, c.repository_id, c.hash, c.message, c.author_name, c.author_email, c.commit_date, c.url,
+               c.author_date, c.author_login, c.author_id, c.committer_name, c.committer_email, c.committer_login, c.committer_id,
+               c.parent_shas, c.tree_sha, c.verified, c.verification_reason, c.html_url, c.additions, c.deletions, c.total_changes, c.files_truncated, c.unreachable`
+
+// streamBatchSize is how many commits StreamCommits fetches from its cursor at a time.
+const streamBatchSize = 500
+
+// commitFilter restricts the commits of the repository named $1 and owned by $2 to those
+// matching a domain.CommitFilter, with its values bound by commitFilterArgs. Empty values do not
+// filter.
+const commitFilter = `
+        WHERE r.name = $1 AND r.owner = $2
+          AND ($3 = '' OR EXISTS (SELECT 1 FROM commit_branches cb WHERE cb.commit_id = c.id AND cb.branch = $3))
+          AND ($4 = '' OR lower(c.author_name) = lower($4) OR lower(c.author_email) = lower($4))
+          AND ($5::timestamptz IS NULL OR c.commit_date >= $5)
+          AND ($6::timestamptz IS NULL OR c.commit_date <= $6)
+          AND ($7 = '' OR to_tsvector('english', c.message) @@ websearch_to_tsquery('english', $7))
+          AND ($8 = '' OR EXISTS (SELECT 1 FROM commit_files f WHERE f.commit_id = c.id AND (f.path = $8 OR f.path LIKE $9)))
+          AND ($10::boolean IS NULL OR (cardinality(c.parent_shas) > 1) = $10)`
+
+// Keyset conditions continuing a scan of commits after or before the commit date and ID bound
+// to $11 and $12.
+const (
+	commitsBefore = `
+          AND (c.commit_date, c.id) < ($11, $12)`
+	commitsAfter = `
+          AND (c.commit_date, c.id) > ($11, $12)`
+)
+
+// Keyset conditions and orders of a scan of commit authors by descending commit count from the
+// count, name and email bound to $8, $9 and $10.
+const (
+	authorsAfter = `
+        WHERE a.commit_count < $8 OR (a.commit_count = $8 AND (a.author_name, a.author_email) > ($9, $10))`
+	authorsBefore = `
+        WHERE a.commit_count > $8 OR (a.commit_count = $8 AND (a.author_name, a.author_email) < ($9, $10))`
+	authorsForward  = "a.commit_count DESC, a.author_name ASC, a.author_email ASC"
+	authorsBackward = "a.commit_count ASC, a.author_name DESC, a.author_email DESC"
+)
+
+// commitSortOrders are the ORDER BY clauses of the commit sort orders.
+var commitSortOrders = map[string]string{
+	domain.CommitSortNewest: "c.commit_date DESC, c.id DESC",
+	domain.CommitSortOldest: "c.commit_date ASC, c.id ASC",
+}
+
+// commitFilterArgs returns the arguments of commitFilter.
+func commitFilterArgs(owner, name string, filter domain.CommitFilter) []interface{} {
+	return []interface{}{
+		name,
+		owner,
+		filter.Branch,
+		filter.Author,
+		filter.Since,
+		filter.Until,
+		filter.Query,
+		filter.Path,
+		escapeLike(filter.Path) + "/%",
+		filter.Merge,
+	}
+}
+
+// commitOrder returns the ORDER BY clause of a commit sort order, newest first by default.
+func commitOrder(sort string) string {
+	if order, ok := commitSortOrders[sort]; ok {
+		return order
+	}
+	return commitSortOrders[domain.CommitSortNewest]
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 type commitRepository struct {
 	db *sqlx.DB
 }
@@ -17,9 +96,21 @@ type commitRepository struct {
 type CommitRepository interface {
 	Save(ctx context.Context, commits []domain.Commit) error
 	GetLatestCommitByRepositoryID(ctx context.Context, repoID int64) (*domain.Commit, error)
-	GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]domain.Commit, int, error)
+	GetLatestCommitByBranch(ctx context.Context, repoID int64, branch string) (*domain.Commit, error)
+	GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, int, error)
+	GetCommitsByCursor(ctx context.Context, owner, name string, filter domain.CommitFilter, cursor *pagination.Cursor, limit int) ([]domain.Commit, error)
+	CountCommits(ctx context.Context, owner, name string, filter domain.CommitFilter) (int, error)
+	CountCommitsSince(ctx context.Context, repoID int64, since time.Time) (int, error)
+	StreamCommits(ctx context.Context, owner, name string, filter domain.CommitFilter, handleBatch func([]domain.Commit) error) error
 	DeleteCommitsByRepositoryID(ctx context.Context, repoID int64) error
-	GetTopCommitAuthors(ctx context.Context, owner, name string, limit int) ([]domain.CommitAuthor, error)
+	GetTopCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error)
+	GetTopCommitAuthorsByCursor(ctx context.Context, owner, name string, filter domain.AuthorFilter, cursor *pagination.Cursor, limit int) ([]domain.CommitAuthor, error)
+	CountCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter) (int, error)
+	GetCommitActivity(ctx context.Context, owner, name, interval string, since, until time.Time) ([]domain.ActivityBucket, error)
+	GetCommitActivityByAuthor(ctx context.Context, owner, name, interval string, since, until time.Time) ([]domain.AuthorActivity, error)
+	GetPunchCard(ctx context.Context, owner, name string, since, until time.Time) ([]domain.PunchCardEntry, error)
+	RecordHistoryRewrite(ctx context.Context, rewrite *domain.HistoryRewrite, since time.Time, reachable []string) (*time.Time, error)
+	GetHistoryRewrites(ctx context.Context, owner, name string) ([]domain.HistoryRewrite, error)
 	BeginTx(ctx context.Context) (*sqlx.Tx, error)
 }
 
@@ -27,27 +118,116 @@ func NewCommitRepository(db *sqlx.DB) CommitRepository {
 	return &commitRepository{db: db}
 }
 
-// Save inserts new commits into the database. Commits with existing hashes are ignored.
+// Save inserts new commits into the database. Commits already stored for their repository are
+// kept unless they lack metadata, but the branches they were collected from are still recorded and they are no longer
+// unreachable. The commits are resolved to their contributors, seeding those of new GitHub logins.
 func (c commitRepository) Save(ctx context.Context, commits []domain.Commit) error {
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Commits stored without their author date, because they came from a push webhook or were
+	// stored before the author was told apart from the committer, take the full metadata.
 	query := `
-        INSERT INTO commits (repository_id, hash, message, author_name, author_email, commit_date, url)
-        VALUES (:repository_id, :hash, :message, :author_name, :author_email, :commit_date, :url)
-        ON CONFLICT (hash) DO NOTHING;
+        INSERT INTO commits (repository_id, hash, message, author_name, author_email, commit_date, url,
+                             author_date, author_login, author_id, committer_name, committer_email, committer_login, committer_id,
+                             parent_shas, tree_sha, verified, verification_reason, html_url)
+        VALUES (:repository_id, :hash, :message, :author_name, :author_email, :commit_date, :url,
+                :author_date, :author_login, :author_id, :committer_name, :committer_email, :committer_login, :committer_id,
+                COALESCE(:parent_shas, '{}'), :tree_sha, :verified, :verification_reason, :html_url)
+        ON CONFLICT (repository_id, hash) DO UPDATE SET
+            author_name = EXCLUDED.author_name,
+            author_email = EXCLUDED.author_email,
+            author_date = EXCLUDED.author_date,
+            author_login = EXCLUDED.author_login,
+            author_id = EXCLUDED.author_id,
+            committer_name = EXCLUDED.committer_name,
+            committer_email = EXCLUDED.committer_email,
+            committer_login = EXCLUDED.committer_login,
+            committer_id = EXCLUDED.committer_id,
+            parent_shas = EXCLUDED.parent_shas,
+            tree_sha = EXCLUDED.tree_sha,
+            verified = EXCLUDED.verified,
+            verification_reason = EXCLUDED.verification_reason,
+            html_url = EXCLUDED.html_url
+        WHERE commits.author_date IS NULL AND EXCLUDED.author_date IS NOT NULL;
     `
-	_, err := c.db.NamedExecContext(ctx, query, commits)
-	if err != nil {
+	if _, err := tx.NamedExecContext(ctx, query, uniqueCommits(commits)); err != nil {
 		return fmt.Errorf("database save error: %w", err)
 	}
+
+	var repoIDs, allRepoIDs []int64
+	var hashes, allHashes, branches []string
+	for _, commit := range commits {
+		allRepoIDs = append(allRepoIDs, commit.RepositoryID)
+		allHashes = append(allHashes, commit.Hash)
+		if commit.Branch != "" {
+			repoIDs = append(repoIDs, commit.RepositoryID)
+			hashes = append(hashes, commit.Hash)
+			branches = append(branches, commit.Branch)
+		}
+	}
+
+	// Commits collected again are back on a branch.
+	reachableQuery := `
+        UPDATE commits SET unreachable = FALSE
+        FROM unnest($1::bigint[], $2::text[]) AS m(repository_id, hash)
+        WHERE commits.unreachable AND commits.repository_id = m.repository_id AND commits.hash = m.hash;
+    `
+	if _, err := tx.ExecContext(ctx, reachableQuery, pq.Array(allRepoIDs), pq.Array(allHashes)); err != nil {
+		return fmt.Errorf("failed to mark commits reachable: %w", err)
+	}
+
+	if _, err := syncContributors(ctx, tx, savedCommits, pq.Array(allRepoIDs), pq.Array(allHashes)); err != nil {
+		return err
+	}
+
+	if len(hashes) > 0 {
+		membershipQuery := `
+            INSERT INTO commit_branches (commit_id, branch)
+            SELECT c.id, m.branch
+            FROM unnest($1::bigint[], $2::text[], $3::text[]) AS m(repository_id, hash, branch)
+            JOIN commits c ON c.repository_id = m.repository_id AND c.hash = m.hash
+            ON CONFLICT DO NOTHING;
+        `
+		if _, err := tx.ExecContext(ctx, membershipQuery, pq.Array(repoIDs), pq.Array(hashes), pq.Array(branches)); err != nil {
+			return fmt.Errorf("failed to save commit branches: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
-// GetLatestCommitByRepositoryID retrieves the most recent commit for a specified repository.
+// uniqueCommits drops the repeated commits of a batch, which a single upsert cannot update twice.
+func uniqueCommits(commits []domain.Commit) []domain.Commit {
+	type commitKey struct {
+		repositoryID int64
+		hash         string
+	}
+	seen := make(map[commitKey]bool, len(commits))
+	unique := make([]domain.Commit, 0, len(commits))
+	for _, commit := range commits {
+		key := commitKey{commit.RepositoryID, commit.Hash}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, commit)
+		}
+	}
+	return unique
+}
+
+// GetLatestCommitByRepositoryID retrieves the most recent reachable commit for a specified repository.
 func (c commitRepository) GetLatestCommitByRepositoryID(ctx context.Context, repoID int64) (*domain.Commit, error) {
 	query := `
-        SELECT id, repository_id, hash, message, author_name, author_email, commit_date, url
-        FROM commits
-        WHERE repository_id = $1
-        ORDER BY commit_date DESC
+        SELECT ` + commitColumns + `
+        FROM commits c
+        WHERE c.repository_id = $1 AND NOT c.unreachable
+        ORDER BY c.commit_date DESC
         LIMIT 1;
     `
 	var commit domain.Commit
@@ -60,34 +240,229 @@ func (c commitRepository) GetLatestCommitByRepositoryID(ctx context.Context, rep
 	return &commit, nil
 }
 
-func (c commitRepository) GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]domain.Commit, int, error) {
+// GetCommitsByRepositoryName retrieves a page of the commits of a repository that match filter.
+func (c commitRepository) GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, int, error) {
+	args := commitFilterArgs(owner, name, filter)
 	query := `
-        SELECT commits.id, commits.repository_id, commits.hash, commits.message, commits.author_name, 
-               commits.author_email, commits.commit_date, commits.url
-        FROM commits
-        JOIN repositories ON commits.repository_id = repositories.id
-        WHERE repositories.name = $1 AND repositories.owner = $2
-        ORDER BY commits.commit_date DESC
-    `
+        SELECT ` + commitColumns + `
+        FROM commits c
+        JOIN repositories r ON c.repository_id = r.id` + commitFilter + `
+        ORDER BY ` + commitOrder(filter.Sort)
 	paginatedQuery := pagination.ApplyToQuery(query, page, pageSize)
 
 	var commits []domain.Commit
-	if err := c.db.SelectContext(ctx, &commits, paginatedQuery, name, owner); err != nil {
+	if err := c.db.SelectContext(ctx, &commits, paginatedQuery, args...); err != nil {
 		return nil, 0, fmt.Errorf("failed to get commits by repository name: %w", err)
 	}
 
 	// Count total items for pagination
-	var totalItems int
-	countQuery := `SELECT COUNT(*) FROM commits
-                   JOIN repositories ON commits.repository_id = repositories.id
-                   WHERE repositories.name = $1 AND repositories.owner = $2`
-	if err := c.db.GetContext(ctx, &totalItems, countQuery, name, owner); err != nil {
-		return nil, 0, fmt.Errorf("failed to count total commits: %w", err)
+	totalItems, err := c.CountCommits(ctx, owner, name, filter)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return commits, totalItems, nil
 }
 
+// GetCommitsByCursor retrieves up to limit commits of a repository that match filter, scanning
+// from cursor in the sort order of filter, or against it from a backward cursor. The commits
+// are returned in scan order.
+func (c commitRepository) GetCommitsByCursor(ctx context.Context, owner, name string, filter domain.CommitFilter, cursor *pagination.Cursor, limit int) ([]domain.Commit, error) {
+	descending := filter.Sort != domain.CommitSortOldest
+	if cursor != nil && cursor.Backward {
+		descending = !descending
+	}
+
+	order := commitSortOrders[domain.CommitSortOldest]
+	keyset := commitsAfter
+	if descending {
+		order = commitSortOrders[domain.CommitSortNewest]
+		keyset = commitsBefore
+	}
+
+	args := commitFilterArgs(owner, name, filter)
+	query := `
+        SELECT ` + commitColumns + `
+        FROM commits c
+        JOIN repositories r ON c.repository_id = r.id` + commitFilter
+	if cursor != nil {
+		query += keyset
+		args = append(args, cursor.Time, cursor.ID)
+	}
+	query = fmt.Sprintf("%s\n        ORDER BY %s\n        LIMIT %d", query, order, limit)
+
+	var commits []domain.Commit
+	if err := c.db.SelectContext(ctx, &commits, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get commits by cursor: %w", err)
+	}
+	return commits, nil
+}
+
+// CountCommitsSince counts the reachable commits of a repository made since the given time.
+func (c commitRepository) CountCommitsSince(ctx context.Context, repoID int64, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM commits WHERE repository_id = $1 AND commit_date >= $2 AND NOT unreachable`
+	if err := c.db.GetContext(ctx, &count, query, repoID, since); err != nil {
+		return 0, fmt.Errorf("failed to count recent commits: %w", err)
+	}
+	return count, nil
+}
+
+// CountCommits counts the commits of a repository that match filter.
+func (c commitRepository) CountCommits(ctx context.Context, owner, name string, filter domain.CommitFilter) (int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM commits c
+                   JOIN repositories r ON c.repository_id = r.id` + commitFilter
+	if err := c.db.GetContext(ctx, &totalItems, countQuery, commitFilterArgs(owner, name, filter)...); err != nil {
+		return 0, fmt.Errorf("failed to count total commits: %w", err)
+	}
+	return totalItems, nil
+}
+
+// StreamCommits hands the commits of a repository that match filter to handleBatch a batch at a
+// time, in the sort order of filter. They are read through a server-side cursor, so that they
+// are never all held in memory. Streaming stops at the first error of handleBatch.
+func (c commitRepository) StreamCommits(ctx context.Context, owner, name string, filter domain.CommitFilter, handleBatch func([]domain.Commit) error) error {
+	tx, err := c.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	declareQuery := `
+        DECLARE commit_stream NO SCROLL CURSOR FOR
+        SELECT ` + commitColumns + `
+        FROM commits c
+        JOIN repositories r ON c.repository_id = r.id` + commitFilter + `
+        ORDER BY ` + commitOrder(filter.Sort)
+	if _, err := tx.ExecContext(ctx, declareQuery, commitFilterArgs(owner, name, filter)...); err != nil {
+		return fmt.Errorf("failed to open commits cursor: %w", err)
+	}
+
+	fetchQuery := fmt.Sprintf("FETCH %d FROM commit_stream", streamBatchSize)
+	for {
+		var commits []domain.Commit
+		if err := tx.SelectContext(ctx, &commits, fetchQuery); err != nil {
+			return fmt.Errorf("failed to fetch commits from cursor: %w", err)
+		}
+		if len(commits) == 0 {
+			break
+		}
+		if err := handleBatch(commits); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetLatestCommitByBranch retrieves the most recent reachable commit collected from a branch of a repository.
+func (c commitRepository) GetLatestCommitByBranch(ctx context.Context, repoID int64, branch string) (*domain.Commit, error) {
+	query := `
+        SELECT ` + commitColumns + `
+        FROM commits c
+        JOIN commit_branches cb ON cb.commit_id = c.id
+        WHERE c.repository_id = $1 AND cb.branch = $2 AND NOT c.unreachable
+        ORDER BY c.commit_date DESC
+        LIMIT 1;
+    `
+	var commit domain.Commit
+	if err := c.db.GetContext(ctx, &commit, query, repoID, branch); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest commit of branch: %w", err)
+	}
+	return &commit, nil
+}
+
+// RecordHistoryRewrite records that the history of a branch was rewritten. The commits of the
+// branch dated after since are removed from it, except those whose hash is in reachable, and
+// those of them left on no branch at all are marked unreachable. It sets the ID, detection date
+// and number of orphaned commits of rewrite and returns the date of the oldest orphaned commit,
+// nil when there are none.
+func (c commitRepository) RecordHistoryRewrite(ctx context.Context, rewrite *domain.HistoryRewrite, since time.Time, reachable []string) (*time.Time, error) {
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	membershipQuery := `
+        DELETE FROM commit_branches cb
+        USING commits c
+        WHERE cb.commit_id = c.id AND c.repository_id = $1 AND cb.branch = $2 AND c.commit_date > $3
+          AND c.hash <> ALL(COALESCE($4::text[], '{}'))
+        RETURNING cb.commit_id;
+    `
+	var removedIDs []int64
+	if err := tx.SelectContext(ctx, &removedIDs, membershipQuery, rewrite.RepositoryID, rewrite.Branch, since, pq.Array(reachable)); err != nil {
+		return nil, fmt.Errorf("failed to remove commits from branch: %w", err)
+	}
+
+	// Only commits taken off the branch can be orphaned, so commits that were never recorded on
+	// a branch are left alone.
+	orphanQuery := `
+        UPDATE commits SET unreachable = TRUE
+        WHERE id = ANY($1) AND NOT unreachable
+          AND NOT EXISTS (SELECT 1 FROM commit_branches cb WHERE cb.commit_id = commits.id)
+        RETURNING commit_date;
+    `
+	var orphanDates []time.Time
+	if err := tx.SelectContext(ctx, &orphanDates, orphanQuery, pq.Array(removedIDs)); err != nil {
+		return nil, fmt.Errorf("failed to mark commits unreachable: %w", err)
+	}
+
+	rewrite.OrphanedCommits = len(orphanDates)
+	rewriteQuery := `
+        INSERT INTO history_rewrites (repository_id, branch, before_sha, after_sha, merge_base_sha, orphaned_commits)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, detected_at;
+    `
+	err = tx.QueryRowContext(ctx, rewriteQuery,
+		rewrite.RepositoryID,
+		rewrite.Branch,
+		rewrite.BeforeSHA,
+		rewrite.AfterSHA,
+		rewrite.MergeBaseSHA,
+		rewrite.OrphanedCommits,
+	).Scan(&rewrite.ID, &rewrite.DetectedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record history rewrite: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	var oldest *time.Time
+	for i := range orphanDates {
+		if oldest == nil || orphanDates[i].Before(*oldest) {
+			oldest = &orphanDates[i]
+		}
+	}
+	return oldest, nil
+}
+
+// GetHistoryRewrites retrieves the history rewrites detected for a repository, latest first.
+func (c commitRepository) GetHistoryRewrites(ctx context.Context, owner, name string) ([]domain.HistoryRewrite, error) {
+	query := `
+        SELECT h.id, h.repository_id, h.branch, h.before_sha, h.after_sha, h.merge_base_sha, h.orphaned_commits, h.detected_at
+        FROM history_rewrites h
+        JOIN repositories r ON h.repository_id = r.id
+        WHERE r.name = $1 AND r.owner = $2
+        ORDER BY h.detected_at DESC;
+    `
+	var rewrites []domain.HistoryRewrite
+	if err := c.db.SelectContext(ctx, &rewrites, query, name, owner); err != nil {
+		return nil, fmt.Errorf("failed to get history rewrites: %w", err)
+	}
+	return rewrites, nil
+}
+
 // DeleteCommitsByRepositoryID deletes all commits for a specified repository.
 func (c commitRepository) DeleteCommitsByRepositoryID(ctx context.Context, repoID int64) error {
 	query := `
@@ -100,24 +475,183 @@ func (c commitRepository) DeleteCommitsByRepositoryID(ctx context.Context, repoI
 	return nil
 }
 
-// GetTopCommitAuthors retrieves the top N authors by commit count for a specified repository.
-func (c commitRepository) GetTopCommitAuthors(ctx context.Context, owner, name string, limit int) ([]domain.CommitAuthor, error) {
-	query := `
-        SELECT c.author_name, c.author_email, COUNT(*) AS commit_count
+// botAuthor matches the commits of GitHub Apps and other bots, whose login, name or noreply
+// email ends in [bot].
+const botAuthor = `COALESCE(c.author_login, '') LIKE '%[bot]'
+               OR COALESCE(c.author_name, '') LIKE '%[bot]'
+               OR COALESCE(c.author_email, '') LIKE '%[bot]@users.noreply.github.com'`
+
+// authorName and authorEmail identify the author of the commit c by the name and email of its
+// contributor p, if any.
+const (
+	authorName  = `COALESCE(p.name, c.author_name, '')`
+	authorEmail = `COALESCE(p.email, c.author_email, '')`
+)
+
+// commitAuthors groups by author, under the identity of their contributor, the reachable commits
+// of the repository named $1 and owned by $2, or of every monitored repository when both are
+// empty, matching a domain.AuthorFilter with its values bound by authorFilterArgs. Stopped
+// repositories only count when they are asked for by name.
+const commitAuthors = `
+        SELECT ` + authorName + ` AS author_name, ` + authorEmail + ` AS author_email, COUNT(*) AS commit_count,
+               MIN(c.commit_date) AS first_commit_date, MAX(c.commit_date) AS last_commit_date,
+               COUNT(DISTINCT (c.commit_date AT TIME ZONE 'UTC')::date) AS active_days,
+               COUNT(DISTINCT c.repository_id) AS repositories
         FROM commits c
         INNER JOIN repositories r ON c.repository_id = r.id
-        WHERE r.name = $1 AND r.owner = $2
-        GROUP BY c.author_name, c.author_email
-        ORDER BY commit_count DESC
-        LIMIT $3;
-    `
+        LEFT JOIN contributors p ON c.contributor_id = p.id
+        WHERE ($1 = '' OR r.name = $1) AND ($2 = '' OR r.owner = $2) AND NOT c.unreachable
+          AND (($1 <> '' AND $2 <> '') OR r.monitoring_status <> '` + domain.RepositoryStatusStopped + `')
+          AND (COALESCE(cardinality($3::text[]), 0) = 0 OR r.owner = ANY($3::text[]))
+          AND (COALESCE(cardinality($4::text[]), 0) = 0 OR r.labels && $4::text[])
+          AND ($5::timestamptz IS NULL OR c.commit_date >= $5)
+          AND ($6::timestamptz IS NULL OR c.commit_date <= $6)
+          AND NOT ($7::boolean AND (` + botAuthor + `))
+        GROUP BY 1, 2`
+
+// authorFilterArgs returns the arguments of commitAuthors.
+func authorFilterArgs(owner, name string, filter domain.AuthorFilter) []interface{} {
+	return []interface{}{
+		name,
+		owner,
+		pq.StringArray(filter.Owners),
+		pq.StringArray(filter.Labels),
+		filter.Since,
+		filter.Until,
+		filter.ExcludeBots,
+	}
+}
+
+// GetTopCommitAuthors retrieves the top N authors by commit count of the commits matching filter
+// of a repository, or of every repository when owner and name are empty.
+func (c commitRepository) GetTopCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error) {
+	query := `
+        SELECT a.*
+        FROM (` + commitAuthors + `) a
+        ORDER BY ` + authorsForward + `
+        LIMIT $8`
 	var authors []domain.CommitAuthor
-	if err := c.db.SelectContext(ctx, &authors, query, name, owner, limit); err != nil {
+	if err := c.db.SelectContext(ctx, &authors, query, append(authorFilterArgs(owner, name, filter), limit)...); err != nil {
 		return nil, fmt.Errorf("failed to get top commit authors: %w", err)
 	}
 	return authors, nil
 }
 
+// GetTopCommitAuthorsByCursor retrieves up to limit authors of the commits matching filter of a
+// repository, or of every repository when owner and name are empty, by descending commit count,
+// then name and email, scanning from cursor, or backwards from a backward cursor. The authors are
+// returned in scan order.
+func (c commitRepository) GetTopCommitAuthorsByCursor(ctx context.Context, owner, name string, filter domain.AuthorFilter, cursor *pagination.Cursor, limit int) ([]domain.CommitAuthor, error) {
+	args := authorFilterArgs(owner, name, filter)
+	query := `
+        SELECT a.*
+        FROM (` + commitAuthors + `) a`
+	order := authorsForward
+	if cursor != nil {
+		keyset := authorsAfter
+		if cursor.Backward {
+			keyset, order = authorsBefore, authorsBackward
+		}
+		if len(cursor.Keys) != 2 {
+			return nil, fmt.Errorf("cursor does not point to an author")
+		}
+		query += keyset
+		args = append(args, cursor.Count, cursor.Keys[0], cursor.Keys[1])
+	}
+	query = fmt.Sprintf("%s\n        ORDER BY %s\n        LIMIT %d", query, order, limit)
+
+	var authors []domain.CommitAuthor
+	if err := c.db.SelectContext(ctx, &authors, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get top commit authors by cursor: %w", err)
+	}
+	return authors, nil
+}
+
+// CountCommitAuthors counts the distinct authors of the commits matching filter of a repository,
+// or of every repository when owner and name are empty.
+func (c commitRepository) CountCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter) (int, error) {
+	var total int
+	if err := c.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM (`+commitAuthors+`) a`, authorFilterArgs(owner, name, filter)...); err != nil {
+		return 0, fmt.Errorf("failed to count commit authors: %w", err)
+	}
+	return total, nil
+}
+
+// activityCommits lists the reachable commits of the repository named $1 and owned by $2 dated
+// from $4 to $5, with the identity of their author and their date in UTC truncated to the
+// interval bound to $3.
+const activityCommits = `
+        SELECT c.id, ` + authorName + ` AS author_name, ` + authorEmail + ` AS author_email,
+               date_trunc($3::text, c.commit_date AT TIME ZONE 'UTC') AS bucket
+        FROM commits c
+        JOIN repositories r ON c.repository_id = r.id
+        LEFT JOIN contributors p ON c.contributor_id = p.id
+        WHERE r.name = $1 AND r.owner = $2 AND NOT c.unreachable
+          AND c.commit_date >= $4::timestamptz AND c.commit_date <= $5::timestamptz`
+
+// GetCommitActivity counts the commits of a repository dated from since to until in buckets of
+// interval, a date_trunc field such as week. Buckets without commits are counted as well.
+func (c commitRepository) GetCommitActivity(ctx context.Context, owner, name, interval string, since, until time.Time) ([]domain.ActivityBucket, error) {
+	query := `
+        SELECT b.bucket, COUNT(a.id) AS commits
+        FROM generate_series(
+                 date_trunc($3::text, $4::timestamptz AT TIME ZONE 'UTC'),
+                 date_trunc($3::text, $5::timestamptz AT TIME ZONE 'UTC'),
+                 ('1 ' || $3::text)::interval
+             ) AS b(bucket)
+        LEFT JOIN (` + activityCommits + `) a ON a.bucket = b.bucket
+        GROUP BY b.bucket
+        ORDER BY b.bucket;
+    `
+	var buckets []domain.ActivityBucket
+	if err := c.db.SelectContext(ctx, &buckets, query, name, owner, interval, since, until); err != nil {
+		return nil, fmt.Errorf("failed to get commit activity: %w", err)
+	}
+	return buckets, nil
+}
+
+// GetCommitActivityByAuthor counts the commits of each author of a repository dated from since
+// to until in buckets of interval. Authors are listed by bucket, then by descending count.
+func (c commitRepository) GetCommitActivityByAuthor(ctx context.Context, owner, name, interval string, since, until time.Time) ([]domain.AuthorActivity, error) {
+	query := `
+        SELECT a.bucket, a.author_name, a.author_email, COUNT(*) AS commits
+        FROM (` + activityCommits + `) a
+        GROUP BY 1, 2, 3
+        ORDER BY 1, 4 DESC, 2, 3;
+    `
+	var activity []domain.AuthorActivity
+	if err := c.db.SelectContext(ctx, &activity, query, name, owner, interval, since, until); err != nil {
+		return nil, fmt.Errorf("failed to get commit activity by author: %w", err)
+	}
+	return activity, nil
+}
+
+// GetPunchCard counts the reachable commits of a repository dated from since to until by
+// weekday and hour in UTC, including those without commits.
+func (c commitRepository) GetPunchCard(ctx context.Context, owner, name string, since, until time.Time) ([]domain.PunchCardEntry, error) {
+	query := `
+        SELECT d.weekday, h.hour, COUNT(a.id) AS commits
+        FROM generate_series(0, 6) AS d(weekday)
+        CROSS JOIN generate_series(0, 23) AS h(hour)
+        LEFT JOIN (
+            SELECT c.id,
+                   EXTRACT(DOW FROM c.commit_date AT TIME ZONE 'UTC')::int AS weekday,
+                   EXTRACT(HOUR FROM c.commit_date AT TIME ZONE 'UTC')::int AS hour
+            FROM commits c
+            JOIN repositories r ON c.repository_id = r.id
+            WHERE r.name = $1 AND r.owner = $2 AND NOT c.unreachable
+              AND c.commit_date >= $3::timestamptz AND c.commit_date <= $4::timestamptz
+        ) a ON a.weekday = d.weekday AND a.hour = h.hour
+        GROUP BY d.weekday, h.hour
+        ORDER BY d.weekday, h.hour;
+    `
+	var entries []domain.PunchCardEntry
+	if err := c.db.SelectContext(ctx, &entries, query, name, owner, since, until); err != nil {
+		return nil, fmt.Errorf("failed to get punch card: %w", err)
+	}
+	return entries, nil
+}
+
 // BeginTx starts a new database transaction.
 func (c commitRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	tx, err := c.db.BeginTxx(ctx, nil)
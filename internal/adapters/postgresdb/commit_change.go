@@ -0,0 +1,188 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+)
+
+type commitChangeRepository struct {
+	db *sqlx.DB
+}
+
+type CommitChangeRepository interface {
+	FindPending(ctx context.Context, limit int, excludedIDs []int64) ([]domain.CommitRef, error)
+	Save(ctx context.Context, commitID int64, changes *domain.CommitChanges) error
+	UpdateStatus(ctx context.Context, commitID int64, status string) error
+	RecordFailure(ctx context.Context, commitID int64, maxAttempts int) (bool, error)
+	MarkPending(ctx context.Context, repoID int64) (int, error)
+	FindFiles(ctx context.Context, owner, name, hash string) ([]domain.CommitFile, error)
+}
+
+func NewCommitChangeRepository(db *sqlx.DB) CommitChangeRepository {
+	return &commitChangeRepository{db: db}
+}
+
+// FindPending retrieves up to limit commits waiting to be enriched, newest first, leaving out
+// those whose ID is in excludedIDs.
+func (r commitChangeRepository) FindPending(ctx context.Context, limit int, excludedIDs []int64) ([]domain.CommitRef, error) {
+	query := `
+        SELECT c.id, c.repository_id, r.owner, r.name, c.hash
+        FROM commits c
+        JOIN repositories r ON c.repository_id = r.id
+        WHERE c.enrichment_status = 'pending' AND c.id <> ALL(COALESCE($2::bigint[], '{}'))
+        ORDER BY c.commit_date DESC
+        LIMIT $1;
+    `
+	var commits []domain.CommitRef
+	if err := r.db.SelectContext(ctx, &commits, query, limit, pq.Array(excludedIDs)); err != nil {
+		return nil, fmt.Errorf("failed to find commits pending enrichment: %w", err)
+	}
+	return commits, nil
+}
+
+// Save stores the line statistics and changed files of a commit and marks it enriched. A commit
+// stored without its author date, because it came from a push webhook or was stored before the
+// author was told apart from the committer, takes the metadata of changes and is resolved to its
+// contributor again.
+func (r commitChangeRepository) Save(ctx context.Context, commitID int64, changes *domain.CommitChanges) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if metadata := changes.Metadata; metadata.AuthorDate != nil {
+		metadataQuery := `
+            UPDATE commits SET
+                author_name = $2, author_email = $3, author_date = $4, author_login = $5, author_id = $6,
+                committer_name = $7, committer_email = $8, committer_login = $9, committer_id = $10,
+                parent_shas = COALESCE($11, '{}'), tree_sha = $12, verified = $13, verification_reason = $14, html_url = $15
+            WHERE id = $1 AND author_date IS NULL;
+        `
+		result, err := tx.ExecContext(ctx, metadataQuery, commitID,
+			metadata.AuthorName, metadata.AuthorEmail, metadata.AuthorDate, metadata.AuthorLogin, metadata.AuthorID,
+			metadata.CommitterName, metadata.CommitterEmail, metadata.CommitterLogin, metadata.CommitterID,
+			metadata.ParentSHAs, metadata.TreeSHA, metadata.Verified, metadata.VerificationReason, metadata.HTMLURL)
+		if err != nil {
+			return fmt.Errorf("failed to save commit metadata: %w", err)
+		}
+		if refilled, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to count refilled commits: %w", err)
+		} else if refilled > 0 {
+			if _, err := syncContributors(ctx, tx, commitsByID, pq.Array([]int64{commitID})); err != nil {
+				return err
+			}
+		}
+	}
+
+	statsQuery := `
+        UPDATE commits SET additions = $2, deletions = $3, total_changes = $4, files_truncated = $5, enrichment_status = 'enriched'
+        WHERE id = $1;
+    `
+	if _, err := tx.ExecContext(ctx, statsQuery, commitID, changes.Additions, changes.Deletions, changes.TotalChanges, changes.FilesTruncated); err != nil {
+		return fmt.Errorf("failed to save commit statistics: %w", err)
+	}
+
+	if len(changes.Files) > 0 {
+		paths := make([]string, len(changes.Files))
+		statuses := make([]string, len(changes.Files))
+		additions := make([]int64, len(changes.Files))
+		deletions := make([]int64, len(changes.Files))
+		previous := make([]string, len(changes.Files))
+		for i, file := range changes.Files {
+			paths[i] = file.Path
+			statuses[i] = file.Status
+			additions[i] = int64(file.Additions)
+			deletions[i] = int64(file.Deletions)
+			previous[i] = file.PreviousFilename
+		}
+
+		filesQuery := `
+            INSERT INTO commit_files (commit_id, path, status, additions, deletions, previous_filename)
+            SELECT $1, f.path, f.status, f.additions, f.deletions, f.previous_filename
+            FROM unnest($2::text[], $3::text[], $4::int[], $5::int[], $6::text[]) AS f(path, status, additions, deletions, previous_filename)
+            ON CONFLICT (commit_id, path) DO NOTHING;
+        `
+		_, err := tx.ExecContext(ctx, filesQuery, commitID,
+			pq.Array(paths), pq.Array(statuses), pq.Array(additions), pq.Array(deletions), pq.Array(previous))
+		if err != nil {
+			return fmt.Errorf("failed to save commit files: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus updates the enrichment status of a commit.
+func (r commitChangeRepository) UpdateStatus(ctx context.Context, commitID int64, status string) error {
+	query := `UPDATE commits SET enrichment_status = $2 WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, commitID, status); err != nil {
+		return fmt.Errorf("failed to update enrichment status: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure records a failed attempt at enriching a commit, marking it failed once it has
+// failed maxAttempts times. It reports whether the commit was marked failed.
+func (r commitChangeRepository) RecordFailure(ctx context.Context, commitID int64, maxAttempts int) (bool, error) {
+	query := `
+        UPDATE commits SET
+            enrichment_attempts = enrichment_attempts + 1,
+            enrichment_status = CASE WHEN enrichment_attempts + 1 >= $2 THEN 'failed' ELSE enrichment_status END
+        WHERE id = $1
+        RETURNING enrichment_status = 'failed';
+    `
+	var failed bool
+	if err := r.db.QueryRowContext(ctx, query, commitID, maxAttempts).Scan(&failed); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to record enrichment failure: %w", err)
+	}
+	return failed, nil
+}
+
+// MarkPending queues the skipped and failed commits of a repository for enrichment, with their
+// failed attempts forgotten, and returns how many were queued. Enriched commits still missing
+// their author date, enriched before their metadata was refilled, are queued again as well.
+func (r commitChangeRepository) MarkPending(ctx context.Context, repoID int64) (int, error) {
+	query := `
+        UPDATE commits SET enrichment_status = 'pending', enrichment_attempts = 0
+        WHERE repository_id = $1
+          AND (enrichment_status IN ('skipped', 'failed') OR (enrichment_status = 'enriched' AND author_date IS NULL));
+    `
+	result, err := r.db.ExecContext(ctx, query, repoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue commits for enrichment: %w", err)
+	}
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count queued commits: %w", err)
+	}
+	return int(queued), nil
+}
+
+// FindFiles retrieves the files changed by a commit of a repository, by path.
+func (r commitChangeRepository) FindFiles(ctx context.Context, owner, name, hash string) ([]domain.CommitFile, error) {
+	query := `
+        SELECT f.commit_id, f.path, f.status, f.additions, f.deletions, f.previous_filename
+        FROM commit_files f
+        JOIN commits c ON f.commit_id = c.id
+        JOIN repositories r ON c.repository_id = r.id
+        WHERE r.name = $1 AND r.owner = $2 AND c.hash = $3
+        ORDER BY f.path;
+    `
+	var files []domain.CommitFile
+	if err := r.db.SelectContext(ctx, &files, query, name, owner, hash); err != nil {
+		return nil, fmt.Errorf("failed to find commit files: %w", err)
+	}
+	return files, nil
+}
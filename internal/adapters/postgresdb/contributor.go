@@ -0,0 +1,489 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+)
+
+// ErrConflict is returned when a change conflicts with an existing record, such as a second
+// contributor with the same login.
+var ErrConflict = errors.New("conflicts with an existing record")
+
+const contributorColumns = `id, name, email, login, created_at, updated_at`
+
+const aliasColumns = `id, contributor_id, name, email, login, source, created_at`
+
+// commitLogin is the lowercase GitHub login of the author of the commit c: its author login, or
+// the login in its noreply email such as 1234+jane@users.noreply.github.com. It is NULL when
+// the author is not known to GitHub.
+const commitLogin = `COALESCE(NULLIF(lower(c.author_login), ''), substring(lower(c.author_email) from '^(?:[0-9]+\+)?([^@]+)@users\.noreply\.github\.com$'))`
+
+// contributorOfCommit selects the contributor of the alias best matching the author of the
+// commit c: manual and mailmap aliases before those seeded from GitHub, then the most specific,
+// then the latest.
+const contributorOfCommit = `
+                SELECT a.contributor_id
+                FROM contributor_aliases a
+                WHERE (a.name = '' OR a.name = c.author_name)
+                  AND (a.email = '' OR a.email = lower(c.author_email))
+                  AND (a.login = '' OR a.login = ` + commitLogin + `)
+                ORDER BY a.source = 'github', (a.name <> '')::int + (a.email <> '')::int + (a.login <> '')::int DESC, a.id DESC
+                LIMIT 1`
+
+// Scopes of the commits whose contributors syncContributors resolves.
+const (
+	// savedCommits are the commits of the repositories and hashes bound to $1 and $2.
+	savedCommits = `(c.repository_id, c.hash) IN (SELECT * FROM unnest($1::bigint[], $2::text[]))`
+	// commitsByID are the commits whose IDs are bound to $1.
+	commitsByID = `c.id = ANY($1)`
+	// unresolvedCommits are the commits without a contributor.
+	unresolvedCommits = `c.contributor_id IS NULL`
+	// contributorCommits are the commits of the contributor bound to $1.
+	contributorCommits = `c.contributor_id = $1`
+	// aliasCommits are the commits matching the name, email and login bound to $1, $2 and $3.
+	aliasCommits = `($1::text = '' OR c.author_name = $1) AND ($2::text = '' OR lower(c.author_email) = $2) AND ($3::text = '' OR ` + commitLogin + ` = $3)`
+	// mailmapCommits are the commits matching any of the names and emails bound to $1 and $2,
+	// pairwise.
+	mailmapCommits = `EXISTS (
+                SELECT 1 FROM unnest($1::text[], $2::text[]) AS m(name, email)
+                WHERE (m.name = '' OR c.author_name = m.name) AND (m.email = '' OR lower(c.author_email) = m.email))`
+)
+
+// syncContributors creates a contributor, known by a login alias, for every GitHub login of the
+// commits in scope that no contributor or alias has yet, then resolves the commits in scope with
+// resolveContributors. The scope is a condition on the commit c with its values bound by args.
+// It returns how many contributors were created.
+func syncContributors(ctx context.Context, tx *sqlx.Tx, scope string, args ...interface{}) (int, error) {
+	seedQuery := `
+        WITH logins AS (
+            SELECT DISTINCT ON (l.login) l.login, l.name, l.email
+            FROM (
+                SELECT ` + commitLogin + ` AS login, COALESCE(c.author_name, '') AS name,
+                       lower(COALESCE(c.author_email, '')) AS email, c.commit_date
+                FROM commits c
+                WHERE c.contributor_id IS NULL AND ` + scope + `
+            ) l
+            WHERE l.login IS NOT NULL
+              AND NOT EXISTS (SELECT 1 FROM contributors p WHERE p.login = l.login)
+              AND NOT EXISTS (SELECT 1 FROM contributor_aliases a WHERE a.login = l.login)
+            ORDER BY l.login, l.email LIKE '%@users.noreply.github.com', l.commit_date DESC
+        ), created AS (
+            INSERT INTO contributors (name, email, login)
+            SELECT COALESCE(NULLIF(name, ''), login), email, login FROM logins
+            ON CONFLICT (login) WHERE login <> '' DO NOTHING
+            RETURNING id, login
+        )
+        INSERT INTO contributor_aliases (contributor_id, login, source)
+        SELECT id, login, 'github' FROM created
+        ON CONFLICT (name, email, login) DO NOTHING;
+    `
+	result, err := tx.ExecContext(ctx, seedQuery, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seed contributors: %w", err)
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count seeded contributors: %w", err)
+	}
+
+	if err := resolveContributors(ctx, tx, scope, args...); err != nil {
+		return 0, err
+	}
+	return int(created), nil
+}
+
+// resolveContributors assigns the commits in scope the contributor of their best matching alias,
+// or none. The scope is a condition on the commit c with its values bound by args.
+func resolveContributors(ctx context.Context, tx *sqlx.Tx, scope string, args ...interface{}) error {
+	resolveQuery := `
+        UPDATE commits SET contributor_id = m.contributor_id
+        FROM (
+            SELECT c.id, (` + contributorOfCommit + `
+            ) AS contributor_id
+            FROM commits c
+            WHERE ` + scope + `
+        ) m
+        WHERE commits.id = m.id AND commits.contributor_id IS DISTINCT FROM m.contributor_id;
+    `
+	if _, err := tx.ExecContext(ctx, resolveQuery, args...); err != nil {
+		return fmt.Errorf("failed to resolve commit contributors: %w", err)
+	}
+	return nil
+}
+
+type contributorRepository struct {
+	db *sqlx.DB
+}
+
+type ContributorRepository interface {
+	FindAll(ctx context.Context) ([]domain.Contributor, error)
+	FindByID(ctx context.Context, id int64) (*domain.Contributor, error)
+	Create(ctx context.Context, contributor *domain.Contributor) error
+	Update(ctx context.Context, contributor *domain.Contributor) error
+	Delete(ctx context.Context, id int64) error
+	Merge(ctx context.Context, id, mergedID int64) error
+	SaveAlias(ctx context.Context, alias *domain.ContributorAlias) error
+	DeleteAlias(ctx context.Context, contributorID, aliasID int64) (bool, error)
+	ImportMailmap(ctx context.Context, entries []domain.MailmapEntry) error
+	Seed(ctx context.Context) (int, error)
+}
+
+func NewContributorRepository(db *sqlx.DB) ContributorRepository {
+	return &contributorRepository{db: db}
+}
+
+// FindAll retrieves every contributor with its aliases, by name.
+func (r contributorRepository) FindAll(ctx context.Context) ([]domain.Contributor, error) {
+	var contributors []domain.Contributor
+	if err := r.db.SelectContext(ctx, &contributors, `SELECT `+contributorColumns+` FROM contributors ORDER BY name, id`); err != nil {
+		return nil, fmt.Errorf("failed to find contributors: %w", err)
+	}
+	var aliases []domain.ContributorAlias
+	if err := r.db.SelectContext(ctx, &aliases, `SELECT `+aliasColumns+` FROM contributor_aliases ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("failed to find contributor aliases: %w", err)
+	}
+
+	byID := make(map[int64]*domain.Contributor, len(contributors))
+	for i := range contributors {
+		contributors[i].Aliases = []domain.ContributorAlias{}
+		byID[contributors[i].ID] = &contributors[i]
+	}
+	for _, alias := range aliases {
+		if contributor, ok := byID[alias.ContributorID]; ok {
+			contributor.Aliases = append(contributor.Aliases, alias)
+		}
+	}
+	return contributors, nil
+}
+
+// FindByID retrieves a contributor with its aliases.
+func (r contributorRepository) FindByID(ctx context.Context, id int64) (*domain.Contributor, error) {
+	var contributor domain.Contributor
+	if err := r.db.GetContext(ctx, &contributor, `SELECT `+contributorColumns+` FROM contributors WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find contributor: %w", err)
+	}
+	contributor.Aliases = []domain.ContributorAlias{}
+	if err := r.db.SelectContext(ctx, &contributor.Aliases, `SELECT `+aliasColumns+` FROM contributor_aliases WHERE contributor_id = $1 ORDER BY id`, id); err != nil {
+		return nil, fmt.Errorf("failed to find contributor aliases: %w", err)
+	}
+	return &contributor, nil
+}
+
+// Create inserts a contributor with its aliases, taking over those already given to other
+// contributors, and assigns it the commits they match. It sets the IDs and timestamps.
+func (r contributorRepository) Create(ctx context.Context, contributor *domain.Contributor) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO contributors (name, email, login)
+        VALUES ($1, $2, $3)
+        RETURNING id, created_at, updated_at;
+    `
+	err = tx.QueryRowxContext(ctx, query, contributor.Name, contributor.Email, contributor.Login).
+		Scan(&contributor.ID, &contributor.CreatedAt, &contributor.UpdatedAt)
+	if err != nil {
+		return contributorError("failed to create contributor", err)
+	}
+
+	for i := range contributor.Aliases {
+		contributor.Aliases[i].ContributorID = contributor.ID
+		if err := saveAlias(ctx, tx, &contributor.Aliases[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Update sets the name, email and login of a contributor and its update time. The contributor
+// is given an alias of its login unless it has one, taken over from the contributor that has it
+// already, and the alias of a former login is dropped, with the commits of both resolved again.
+func (r contributorRepository) Update(ctx context.Context, contributor *domain.Contributor) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previousLogin string
+	if err := tx.QueryRowxContext(ctx, `SELECT login FROM contributors WHERE id = $1 FOR UPDATE`, contributor.ID).Scan(&previousLogin); err != nil {
+		return contributorError("failed to update contributor", err)
+	}
+
+	query := `
+        UPDATE contributors SET name = $1, email = $2, login = $3, updated_at = NOW()
+        WHERE id = $4
+        RETURNING updated_at;
+    `
+	err = tx.QueryRowxContext(ctx, query, contributor.Name, contributor.Email, contributor.Login, contributor.ID).Scan(&contributor.UpdatedAt)
+	if err != nil {
+		return contributorError("failed to update contributor", err)
+	}
+
+	if previousLogin != "" && previousLogin != contributor.Login {
+		aliasQuery := `DELETE FROM contributor_aliases WHERE contributor_id = $1 AND name = '' AND email = '' AND login = $2`
+		if _, err := tx.ExecContext(ctx, aliasQuery, contributor.ID, previousLogin); err != nil {
+			return fmt.Errorf("failed to delete contributor login alias: %w", err)
+		}
+		if err := resolveContributors(ctx, tx, contributorCommits, contributor.ID); err != nil {
+			return err
+		}
+	}
+	if contributor.Login != "" {
+		var hasAlias bool
+		aliasQuery := `SELECT EXISTS (SELECT 1 FROM contributor_aliases WHERE contributor_id = $1 AND name = '' AND email = '' AND login = $2)`
+		if err := tx.QueryRowxContext(ctx, aliasQuery, contributor.ID, contributor.Login).Scan(&hasAlias); err != nil {
+			return fmt.Errorf("failed to find contributor login alias: %w", err)
+		}
+		if !hasAlias {
+			alias := domain.ContributorAlias{ContributorID: contributor.ID, Login: contributor.Login, Source: domain.ContributorSourceManual}
+			if err := saveAlias(ctx, tx, &alias); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a contributor and its aliases. Only its former commits are resolved again, to
+// the aliases of other contributors, and no contributor is seeded for them.
+func (r contributorRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var commitIDs []int64
+	if err := tx.SelectContext(ctx, &commitIDs, `SELECT id FROM commits WHERE contributor_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to find contributor commits: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM contributors WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete contributor: %w", err)
+	}
+	if err := resolveContributors(ctx, tx, commitsByID, pq.Array(commitIDs)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Merge moves the aliases and commits of the contributor mergedID to the contributor id and
+// deletes it.
+func (r contributorRepository) Merge(ctx context.Context, id, mergedID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE contributor_aliases SET contributor_id = $1 WHERE contributor_id = $2`, id, mergedID); err != nil {
+		return fmt.Errorf("failed to move contributor aliases: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE commits SET contributor_id = $1 WHERE contributor_id = $2`, id, mergedID); err != nil {
+		return fmt.Errorf("failed to move contributor commits: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM contributors WHERE id = $1`, mergedID); err != nil {
+		return fmt.Errorf("failed to delete merged contributor: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SaveAlias gives an alias to its contributor, taking it over from the contributor that has it
+// already, and assigns the contributor the commits it matches. It sets the ID and creation time.
+func (r contributorRepository) SaveAlias(ctx context.Context, alias *domain.ContributorAlias) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveAlias(ctx, tx, alias); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// saveAlias upserts an alias and resolves the commits it matches.
+func saveAlias(ctx context.Context, tx *sqlx.Tx, alias *domain.ContributorAlias) error {
+	query := `
+        INSERT INTO contributor_aliases (contributor_id, name, email, login, source)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (name, email, login) DO UPDATE SET
+            contributor_id = EXCLUDED.contributor_id,
+            source = EXCLUDED.source
+        RETURNING id, created_at;
+    `
+	err := tx.QueryRowxContext(ctx, query, alias.ContributorID, alias.Name, alias.Email, alias.Login, alias.Source).
+		Scan(&alias.ID, &alias.CreatedAt)
+	if err != nil {
+		return contributorError("failed to save contributor alias", err)
+	}
+	if _, err := syncContributors(ctx, tx, aliasCommits, alias.Name, alias.Email, alias.Login); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteAlias deletes an alias of a contributor and resolves the commits of the contributor
+// again. It reports whether the contributor had the alias.
+func (r contributorRepository) DeleteAlias(ctx context.Context, contributorID, aliasID int64) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM contributor_aliases WHERE id = $1 AND contributor_id = $2`, aliasID, contributorID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete contributor alias: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete contributor alias: %w", err)
+	}
+	if deleted == 0 {
+		return false, nil
+	}
+	if _, err := syncContributors(ctx, tx, contributorCommits, contributorID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// ImportMailmap records the entries of a .mailmap file. The commits of each entry are given to
+// the contributor with its proper email, created if there is none, whose name becomes the
+// proper name. The commits matching the imported aliases are then resolved again.
+func (r contributorRepository) ImportMailmap(ctx context.Context, entries []domain.MailmapEntry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	contributorQuery := `
+        WITH existing AS (
+            UPDATE contributors SET name = COALESCE(NULLIF($2::text, ''), name), updated_at = NOW()
+            WHERE id = (SELECT id FROM contributors WHERE email = $1::text ORDER BY id LIMIT 1)
+            RETURNING id
+        ), created AS (
+            INSERT INTO contributors (name, email)
+            SELECT COALESCE(NULLIF($2::text, ''), NULLIF($3::text, ''), $1::text), $1::text
+            WHERE NOT EXISTS (SELECT 1 FROM existing)
+            RETURNING id
+        )
+        SELECT id FROM existing UNION ALL SELECT id FROM created;
+    `
+	aliasQuery := `
+        INSERT INTO contributor_aliases (contributor_id, name, email, source)
+        VALUES ($1, $2, $3, 'mailmap')
+        ON CONFLICT (name, email, login) DO UPDATE SET
+            contributor_id = EXCLUDED.contributor_id,
+            source = EXCLUDED.source;
+    `
+	// Only the commits matching the imported aliases can change contributor.
+	var names, emails []string
+	for _, entry := range entries {
+		email := entry.ProperEmail
+		if email == "" {
+			email = entry.CommitEmail
+		}
+
+		var id int64
+		if err := tx.QueryRowxContext(ctx, contributorQuery, email, entry.ProperName, entry.CommitName).Scan(&id); err != nil {
+			return fmt.Errorf("failed to save mailmap contributor %s: %w", email, err)
+		}
+		if _, err := tx.ExecContext(ctx, aliasQuery, id, entry.CommitName, entry.CommitEmail); err != nil {
+			return fmt.Errorf("failed to save mailmap alias %s: %w", entry.CommitEmail, err)
+		}
+		names, emails = append(names, entry.CommitName), append(emails, entry.CommitEmail)
+		if entry.ProperEmail != "" && entry.ProperEmail != entry.CommitEmail {
+			properAliasQuery := `
+                INSERT INTO contributor_aliases (contributor_id, email, source)
+                VALUES ($1, $2, 'mailmap')
+                ON CONFLICT (name, email, login) DO NOTHING;
+            `
+			if _, err := tx.ExecContext(ctx, properAliasQuery, id, entry.ProperEmail); err != nil {
+				return fmt.Errorf("failed to save mailmap alias %s: %w", entry.ProperEmail, err)
+			}
+			names, emails = append(names, ""), append(emails, entry.ProperEmail)
+		}
+	}
+	if _, err := syncContributors(ctx, tx, mailmapCommits, pq.Array(names), pq.Array(emails)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Seed creates the contributors of the GitHub logins of the commits without a contributor and
+// resolves those commits. It returns how many contributors were created.
+func (r contributorRepository) Seed(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created, err := syncContributors(ctx, tx, unresolvedCommits)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
+}
+
+// contributorError wraps err, reporting unique violations as ErrConflict and a missing
+// contributor as sql.ErrNoRows.
+func contributorError(message string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return fmt.Errorf("%s: %w", message, ErrConflict)
+		case "23503":
+			return fmt.Errorf("%s: %w", message, sql.ErrNoRows)
+		}
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
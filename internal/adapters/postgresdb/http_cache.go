@@ -0,0 +1,52 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+)
+
+type httpCacheRepository struct {
+	db *sqlx.DB
+}
+
+type HTTPCacheRepository interface {
+	Get(ctx context.Context, url string) (*domain.HTTPCacheEntry, error)
+	Upsert(ctx context.Context, entry *domain.HTTPCacheEntry) error
+}
+
+func NewHTTPCacheRepository(db *sqlx.DB) HTTPCacheRepository {
+	return &httpCacheRepository{db: db}
+}
+
+// Get retrieves the cached validators for a request URL.
+func (r httpCacheRepository) Get(ctx context.Context, url string) (*domain.HTTPCacheEntry, error) {
+	query := `SELECT url, etag, last_modified, updated_at FROM http_cache_entries WHERE url = $1`
+	var entry domain.HTTPCacheEntry
+	if err := r.db.GetContext(ctx, &entry, query, url); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get http cache entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// Upsert inserts or replaces the cached validators for a request URL.
+func (r httpCacheRepository) Upsert(ctx context.Context, entry *domain.HTTPCacheEntry) error {
+	query := `
+        INSERT INTO http_cache_entries (url, etag, last_modified, updated_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (url) DO UPDATE SET
+            etag = EXCLUDED.etag,
+            last_modified = EXCLUDED.last_modified,
+            updated_at = EXCLUDED.updated_at;
+    `
+	if _, err := r.db.ExecContext(ctx, query, entry.URL, entry.ETag, entry.LastModified); err != nil {
+		return fmt.Errorf("failed to upsert http cache entry: %w", err)
+	}
+	return nil
+}
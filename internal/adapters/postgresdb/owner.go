@@ -0,0 +1,71 @@
+package postgresdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+)
+
+type ownerRepository struct {
+	db *sqlx.DB
+}
+
+type OwnerRepository interface {
+	Upsert(ctx context.Context, owner *domain.MonitoredOwner) error
+	FindAll(ctx context.Context) ([]domain.MonitoredOwner, error)
+	Delete(ctx context.Context, owner string) error
+}
+
+func NewOwnerRepository(db *sqlx.DB) OwnerRepository {
+	return &ownerRepository{db: db}
+}
+
+// Upsert records an owner whose repositories are monitored, replacing the filter of an owner
+// that is already recorded, and sets its ID and timestamps. An owner stays managed once it is.
+func (r ownerRepository) Upsert(ctx context.Context, owner *domain.MonitoredOwner) error {
+	query := `
+        INSERT INTO monitored_owners (owner, include, exclude, languages, include_archived, include_forks, managed)
+        VALUES ($1, COALESCE($2, '{}'), COALESCE($3, '{}'), COALESCE($4, '{}'), $5, $6, $7)
+        ON CONFLICT (owner) DO UPDATE SET
+            include = EXCLUDED.include,
+            exclude = EXCLUDED.exclude,
+            languages = EXCLUDED.languages,
+            include_archived = EXCLUDED.include_archived,
+            include_forks = EXCLUDED.include_forks,
+            managed = monitored_owners.managed OR EXCLUDED.managed,
+            updated_at = NOW()
+        RETURNING id, managed, created_at, updated_at;
+    `
+	err := r.db.QueryRowContext(ctx, query,
+		owner.Owner,
+		owner.Include,
+		owner.Exclude,
+		owner.Languages,
+		owner.IncludeArchived,
+		owner.IncludeForks,
+		owner.Managed,
+	).Scan(&owner.ID, &owner.Managed, &owner.CreatedAt, &owner.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert monitored owner: %w", err)
+	}
+	return nil
+}
+
+// FindAll retrieves every owner whose repositories are monitored.
+func (r ownerRepository) FindAll(ctx context.Context) ([]domain.MonitoredOwner, error) {
+	query := `SELECT id, owner, include, exclude, languages, include_archived, include_forks, managed, created_at, updated_at FROM monitored_owners ORDER BY id`
+	var owners []domain.MonitoredOwner
+	if err := r.db.SelectContext(ctx, &owners, query); err != nil {
+		return nil, fmt.Errorf("failed to find monitored owners: %w", err)
+	}
+	return owners, nil
+}
+
+// Delete stops discovering the repositories of an owner.
+func (r ownerRepository) Delete(ctx context.Context, owner string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM monitored_owners WHERE owner = $1`, owner); err != nil {
+		return fmt.Errorf("failed to delete monitored owner: %w", err)
+	}
+	return nil
+}
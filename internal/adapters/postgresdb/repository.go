@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"time"
 )
 
+// repositoryColumns lists the columns of a repository row, in domain.Repository order.
+const repositoryColumns = `id, name, owner, description, url, language, forks_count, stargazers_count, open_issues_count, watchers_count, created_at, updated_at, monitoring_status, schedule, poll_interval_seconds, empty_polls, start_date, end_date, labels, managed, branches, default_branch`
+
 type repositoryRepository struct {
 	db *sqlx.DB
 }
@@ -17,29 +21,59 @@ type repositoryRepository struct {
 type RepositoryRepository interface {
 	Upsert(ctx context.Context, repository *domain.Repository) error
 	FindByNameAndOwner(ctx context.Context, name, owner string) (*domain.Repository, error)
+	FindMonitored(ctx context.Context) ([]domain.Repository, error)
 	GetOwnerAndRepoName(ctx context.Context, repoID int64) (string, string, error)
 	Update(ctx context.Context, repo *domain.Repository) error
+	UpdateMonitoringStatus(ctx context.Context, repoID int64, status string) error
+	UpdateSchedule(ctx context.Context, repoID int64, schedule string) error
+	UpdatePollState(ctx context.Context, repoID int64, intervalSeconds, emptyPolls int) error
+	UpdateSettings(ctx context.Context, repository *domain.Repository) error
+	UpdateBranches(ctx context.Context, repoID int64, branches []string) error
+	UpdateName(ctx context.Context, repoID int64, name string) error
+	FindManaged(ctx context.Context) ([]domain.Repository, error)
+	Delete(ctx context.Context, repoID int64) error
 }
 
 func NewRepositoryRepository(db *sqlx.DB) RepositoryRepository {
 	return &repositoryRepository{db: db}
 }
 
-// Upsert inserts or updates a repository record in the database.
+// Upsert inserts or updates a repository record in the database. The monitoring status and
+// tracked branches of an existing repository are left unchanged and read back into repository.
+// Commits collected from the default branch before its name was known are moved onto it.
 func (r *repositoryRepository) Upsert(ctx context.Context, repository *domain.Repository) error {
 	query := `
-        INSERT INTO repositories (name, owner, description, url, language, forks_count, stargazers_count, open_issues_count, watchers_count, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-        ON CONFLICT (name, owner) DO UPDATE SET
-            description = EXCLUDED.description,
-            url = EXCLUDED.url,
-            language = EXCLUDED.language,
-            forks_count = EXCLUDED.forks_count,
-            stargazers_count = EXCLUDED.stargazers_count,
-            open_issues_count = EXCLUDED.open_issues_count,
-            watchers_count = EXCLUDED.watchers_count,
-            updated_at = EXCLUDED.updated_at
-        RETURNING id;
+        WITH upserted AS (
+            INSERT INTO repositories (name, owner, description, url, language, forks_count, stargazers_count, open_issues_count, watchers_count, created_at, updated_at, default_branch)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+            ON CONFLICT (name, owner) DO UPDATE SET
+                description = EXCLUDED.description,
+                url = EXCLUDED.url,
+                language = EXCLUDED.language,
+                forks_count = EXCLUDED.forks_count,
+                stargazers_count = EXCLUDED.stargazers_count,
+                open_issues_count = EXCLUDED.open_issues_count,
+                watchers_count = EXCLUDED.watchers_count,
+                updated_at = EXCLUDED.updated_at,
+                default_branch = COALESCE(NULLIF(EXCLUDED.default_branch, ''), repositories.default_branch)
+            RETURNING id, monitoring_status, branches, default_branch
+        ), unnamed AS (
+            SELECT cb.commit_id, u.default_branch,
+                   EXISTS (SELECT 1 FROM commit_branches d WHERE d.commit_id = cb.commit_id AND d.branch = u.default_branch) AS named
+            FROM commit_branches cb
+            JOIN commits c ON c.id = cb.commit_id
+            JOIN upserted u ON u.id = c.repository_id
+            WHERE cb.branch = '' AND u.default_branch <> ''
+        ), renamed AS (
+            UPDATE commit_branches cb SET branch = n.default_branch
+            FROM unnamed n
+            WHERE cb.commit_id = n.commit_id AND cb.branch = '' AND NOT n.named
+        ), merged AS (
+            DELETE FROM commit_branches cb
+            USING unnamed n
+            WHERE cb.commit_id = n.commit_id AND cb.branch = '' AND n.named
+        )
+        SELECT id, monitoring_status, branches FROM upserted;
     `
 	err := r.db.QueryRowContext(ctx, query,
 		repository.Name,
@@ -53,7 +87,8 @@ func (r *repositoryRepository) Upsert(ctx context.Context, repository *domain.Re
 		repository.WatchersCount,
 		repository.CreatedAt,
 		repository.UpdatedAt,
-	).Scan(&repository.ID)
+		repository.DefaultBranch,
+	).Scan(&repository.ID, &repository.MonitoringStatus, &repository.Branches)
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			return fmt.Errorf("failed to upsert repository: %w", err)
@@ -63,9 +98,10 @@ func (r *repositoryRepository) Upsert(ctx context.Context, repository *domain.Re
 	return nil
 }
 
-// FindByNameAndOwner retrieves a repository by its name and owner.
+// FindByNameAndOwner retrieves a repository by its name and owner, which GitHub matches
+// case-insensitively.
 func (r repositoryRepository) FindByNameAndOwner(ctx context.Context, name, owner string) (*domain.Repository, error) {
-	query := `SELECT id, name, owner, description, url, language, forks_count, stargazers_count, open_issues_count, watchers_count, created_at, updated_at FROM repositories WHERE name = $1 AND owner = $2`
+	query := `SELECT ` + repositoryColumns + ` FROM repositories WHERE lower(name) = lower($1) AND lower(owner) = lower($2) ORDER BY id LIMIT 1`
 	var repository domain.Repository
 	err := r.db.GetContext(ctx, &repository, query, name, owner)
 	if err != nil {
@@ -77,6 +113,26 @@ func (r repositoryRepository) FindByNameAndOwner(ctx context.Context, name, owne
 	return &repository, nil
 }
 
+// FindMonitored retrieves every repository that has not been stopped, paused ones included.
+func (r repositoryRepository) FindMonitored(ctx context.Context) ([]domain.Repository, error) {
+	query := `SELECT ` + repositoryColumns + ` FROM repositories WHERE monitoring_status <> $1 ORDER BY id`
+	var repositories []domain.Repository
+	if err := r.db.SelectContext(ctx, &repositories, query, domain.RepositoryStatusStopped); err != nil {
+		return nil, fmt.Errorf("failed to find monitored repositories: %w", err)
+	}
+	return repositories, nil
+}
+
+// FindManaged retrieves every repository declared in the repositories file, stopped ones included.
+func (r repositoryRepository) FindManaged(ctx context.Context) ([]domain.Repository, error) {
+	query := `SELECT ` + repositoryColumns + ` FROM repositories WHERE managed ORDER BY id`
+	var repositories []domain.Repository
+	if err := r.db.SelectContext(ctx, &repositories, query); err != nil {
+		return nil, fmt.Errorf("failed to find managed repositories: %w", err)
+	}
+	return repositories, nil
+}
+
 // GetOwnerAndRepoName retrieves the owner and repository name by repository ID.
 func (r repositoryRepository) GetOwnerAndRepoName(ctx context.Context, repoID int64) (string, string, error) {
 	query := `SELECT owner, name FROM repositories WHERE id = $1`
@@ -107,3 +163,96 @@ func (r repositoryRepository) Update(ctx context.Context, repo *domain.Repositor
 	}
 	return nil
 }
+
+// UpdateMonitoringStatus sets whether the repository is still monitored.
+func (r repositoryRepository) UpdateMonitoringStatus(ctx context.Context, repoID int64, status string) error {
+	query := `UPDATE repositories SET monitoring_status = $1 WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, status, repoID); err != nil {
+		return fmt.Errorf("failed to update repository monitoring status: %w", err)
+	}
+	return nil
+}
+
+// UpdateSchedule sets the polling schedule of the repository.
+func (r repositoryRepository) UpdateSchedule(ctx context.Context, repoID int64, schedule string) error {
+	query := `UPDATE repositories SET schedule = $1 WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, schedule, repoID); err != nil {
+		return fmt.Errorf("failed to update repository schedule: %w", err)
+	}
+	return nil
+}
+
+// UpdatePollState records the adaptive polling interval of the repository and its number of
+// consecutive polls without new commits.
+func (r repositoryRepository) UpdatePollState(ctx context.Context, repoID int64, intervalSeconds, emptyPolls int) error {
+	query := `UPDATE repositories SET poll_interval_seconds = $1, empty_polls = $2 WHERE id = $3`
+	if _, err := r.db.ExecContext(ctx, query, intervalSeconds, emptyPolls, repoID); err != nil {
+		return fmt.Errorf("failed to update repository poll state: %w", err)
+	}
+	return nil
+}
+
+// UpdateBranches sets the branches whose commits are collected for the repository.
+func (r repositoryRepository) UpdateBranches(ctx context.Context, repoID int64, branches []string) error {
+	query := `UPDATE repositories SET branches = COALESCE($1, '{}') WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, pq.StringArray(branches), repoID); err != nil {
+		return fmt.Errorf("failed to update repository branches: %w", err)
+	}
+	return nil
+}
+
+// UpdateName renames the repository, keeping its commits and monitoring state.
+func (r repositoryRepository) UpdateName(ctx context.Context, repoID int64, name string) error {
+	query := `UPDATE repositories SET name = $1 WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, name, repoID); err != nil {
+		return fmt.Errorf("failed to update repository name: %w", err)
+	}
+	return nil
+}
+
+// UpdateSettings records the settings declared for the repository in the repositories file.
+func (r repositoryRepository) UpdateSettings(ctx context.Context, repository *domain.Repository) error {
+	query := `UPDATE repositories SET start_date = $1, end_date = $2, branches = COALESCE($3, '{}'), schedule = $4, labels = COALESCE($5, '{}'), managed = $6 WHERE id = $7`
+	_, err := r.db.ExecContext(ctx, query,
+		repository.StartDate,
+		repository.EndDate,
+		repository.Branches,
+		repository.Schedule,
+		repository.Labels,
+		repository.Managed,
+		repository.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update repository settings: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a repository together with its commits, backfill jobs and history rewrites. The
+// repository row is locked first so that commits saved concurrently cannot slip in before it is
+// deleted.
+func (r repositoryRepository) Delete(ctx context.Context, repoID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		`SELECT id FROM repositories WHERE id = $1 FOR UPDATE`,
+		`DELETE FROM commits WHERE repository_id = $1`,
+		`DELETE FROM backfill_jobs WHERE repository_id = $1`,
+		`DELETE FROM history_rewrites WHERE repository_id = $1`,
+		`DELETE FROM repositories WHERE id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, repoID); err != nil {
+			return fmt.Errorf("failed to delete repository: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit repository deletion: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"time"
+)
+
+// deliveryClaimTimeout is how long a delivery may stay processing before it is deemed abandoned,
+// by a crash or restart, and can be claimed again.
+const deliveryClaimTimeout = 10 * time.Minute
+
+type webhookDeliveryRepository struct {
+	db *sqlx.DB
+}
+
+type WebhookDeliveryRepository interface {
+	Claim(ctx context.Context, delivery *domain.WebhookDelivery) (bool, error)
+	Finish(ctx context.Context, deliveryID, status, errMsg string) error
+}
+
+func NewWebhookDeliveryRepository(db *sqlx.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+// Claim records a delivery as processing. It returns false when the delivery was already
+// seen and did not fail, so redeliveries of failed deliveries are processed again, as are those
+// of deliveries left processing for longer than deliveryClaimTimeout.
+func (r webhookDeliveryRepository) Claim(ctx context.Context, delivery *domain.WebhookDelivery) (bool, error) {
+	query := `
+        INSERT INTO webhook_deliveries (delivery_id, event, status, payload)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (delivery_id) DO UPDATE SET
+            status = EXCLUDED.status,
+            error = '',
+            attempts = webhook_deliveries.attempts + 1,
+            payload = EXCLUDED.payload,
+            received_at = NOW(),
+            processed_at = NULL
+        WHERE webhook_deliveries.status = 'failed'
+           OR (webhook_deliveries.status = $3 AND webhook_deliveries.received_at < NOW() - $5 * INTERVAL '1 second')
+        RETURNING id;
+    `
+	err := r.db.QueryRowContext(ctx, query, delivery.DeliveryID, delivery.Event, domain.WebhookStatusProcessing, delivery.Payload, int(deliveryClaimTimeout/time.Second)).Scan(&delivery.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	return true, nil
+}
+
+// Finish records the outcome of processing a delivery.
+func (r webhookDeliveryRepository) Finish(ctx context.Context, deliveryID, status, errMsg string) error {
+	query := `UPDATE webhook_deliveries SET status = $1, error = $2, processed_at = NOW() WHERE delivery_id = $3`
+	if _, err := r.db.ExecContext(ctx, query, status, errMsg, deliveryID); err != nil {
+		return fmt.Errorf("failed to finish webhook delivery: %w", err)
+	}
+	return nil
+}
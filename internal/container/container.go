@@ -1,10 +1,12 @@
 package container
 
 import (
+	"context"
 	"fmt"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"net/http"
+	"os"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
@@ -12,27 +14,36 @@ import (
 	"github.com/olusolaa/github-monitor/config"
 	"github.com/olusolaa/github-monitor/internal/adapters/github"
 	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/internal/core/services"
 	"github.com/olusolaa/github-monitor/internal/scheduler"
 	"github.com/olusolaa/github-monitor/pkg/httpclient"
+	"github.com/olusolaa/github-monitor/pkg/logger"
 	"github.com/pkg/errors"
 )
 
 type Container struct {
-	cfg            *config.Config
-	dbConn         *sqlx.DB
-	repoService    services.RepositoryService
-	commitService  services.CommitService
-	monitorService *services.MonitorService
-	gitHubService  services.GitHubService
-	scheduler      *scheduler.Scheduler
-	commitChan     chan int64
-	monitoringChan chan int64
+	cfg                *config.Config
+	dbConn             *sqlx.DB
+	repoService        services.RepositoryService
+	commitService      services.CommitService
+	monitorService     *services.MonitorService
+	gitHubService      services.GitHubService
+	webhookService     services.WebhookService
+	ownerService       services.OwnerService
+	enrichmentService  services.EnrichmentService
+	exportService      services.ExportService
+	contributorService services.ContributorService
+	tokenPool          *github.TokenPool
+	scheduler          *scheduler.Scheduler
+	commitChan         chan int64
+	monitoringChan     chan int64
+	removalChan        chan int64
+	rescheduleChan     chan int64
 }
 
 func NewContainer(cfg *config.Config) *Container {
-	connStr := fmt.Sprintf("postgresql://%s:%s@%s:5432/%s?sslmode=disable",
-		cfg.PostgresUser, cfg.PostgresPassword, cfg.PostgresHost, cfg.PostgresDB)
+	connStr := connectionString(cfg)
 
 	dbConn, err := initializeDatabase(connStr)
 	if err != nil {
@@ -43,33 +54,104 @@ func NewContainer(cfg *config.Config) *Container {
 		panic(errors.Wrap(err, "Error running migrations"))
 	}
 
-	githubRateLimiter := github.NewGitHubRateLimiter()
-	ghClient := github.NewClient(cfg.GitHubBaseURL, httpclient.NewClient(http.DefaultClient, githubRateLimiter.RateLimitMiddleware, httpclient.LoggingMiddleware, httpclient.AuthMiddleware(cfg.GitHubToken)))
-
 	repoRepo := postgresdb.NewRepositoryRepository(dbConn)
 	commitRepo := postgresdb.NewCommitRepository(dbConn)
+	httpCacheRepo := postgresdb.NewHTTPCacheRepository(dbConn)
+	webhookDeliveryRepo := postgresdb.NewWebhookDeliveryRepository(dbConn)
+	backfillJobRepo := postgresdb.NewBackfillJobRepository(dbConn)
+	ownerRepo := postgresdb.NewOwnerRepository(dbConn)
+	commitChangeRepo := postgresdb.NewCommitChangeRepository(dbConn)
+	contributorRepo := postgresdb.NewContributorRepository(dbConn)
+
+	middleware, tokenPool, err := newGitHubMiddleware(cfg)
+	if err != nil {
+		panic(errors.Wrap(err, "Error configuring GitHub authentication"))
+	}
+
+	ghClient := github.NewClient(cfg.GitHubBaseURL, httpclient.NewClient(http.DefaultClient, middleware...), httpCacheRepo)
 
 	githubService := services.NewGitHubService(ghClient)
 	commitChan := make(chan int64, 100)     // Initialize commitChan with a buffer size
 	monitoringChan := make(chan int64, 100) // Initialize monitoringChan with a buffer size
+	removalChan := make(chan int64, 10)     // Repositories that stopped being monitored
+	rescheduleChan := make(chan int64, 10)  // Repositories whose polling schedule changed
 
 	repoChan := make(chan services.RepoRequest, 10) // Buffered channel for concurrent requests
-	repoService := services.NewRepositoryService(githubService, repoRepo, repoChan, commitChan)
-	commitService := services.NewCommitService(githubService, repoService, commitRepo, commitChan)
+	repoService := services.NewRepositoryService(githubService, repoRepo, repoChan, commitChan, removalChan, rescheduleChan)
+	commitService := services.NewCommitService(githubService, repoService, commitRepo, backfillJobRepo, commitChan, monitoringChan, cfg.StartDate, cfg.EndDate)
+	webhookService := services.NewWebhookService(repoService, commitService, webhookDeliveryRepo)
+	ownerService := services.NewOwnerService(githubService, repoService, ownerRepo)
+	enrichmentService := services.NewEnrichmentService(githubService, repoService, commitChangeRepo, cfg.EnrichmentConcurrency)
+	exportService := services.NewExportService(repoRepo, commitRepo)
+	contributorService := services.NewContributorService(contributorRepo)
 	monitorService := services.NewMonitorService(repoService, commitService, githubService, cfg.MaxRetries, cfg.InitialBackoff)
-	schedulerService := scheduler.NewScheduler(monitorService, cfg)
+	schedulerService := scheduler.NewScheduler(monitorService, cfg, rescheduleChan)
 
 	return &Container{
-		cfg:            cfg,
-		dbConn:         dbConn,
-		repoService:    repoService,
-		commitService:  commitService,
-		gitHubService:  githubService,
-		monitorService: monitorService,
-		scheduler:      schedulerService,
-		commitChan:     commitChan,
-		monitoringChan: monitoringChan,
+		cfg:                cfg,
+		dbConn:             dbConn,
+		repoService:        repoService,
+		commitService:      commitService,
+		gitHubService:      githubService,
+		webhookService:     webhookService,
+		ownerService:       ownerService,
+		enrichmentService:  enrichmentService,
+		exportService:      exportService,
+		contributorService: contributorService,
+		tokenPool:          tokenPool,
+		monitorService:     monitorService,
+		scheduler:          schedulerService,
+		commitChan:         commitChan,
+		monitoringChan:     monitoringChan,
+		removalChan:        removalChan,
+		rescheduleChan:     rescheduleChan,
+	}
+}
+
+// newGitHubMiddleware builds the rate limiting and authentication middleware for the GitHub
+// client. A GitHub App is used when an app ID is configured; otherwise requests rotate over a
+// pool of GITHUB_TOKENS, falling back to the single GITHUB_TOKEN. The pool is nil in app mode.
+func newGitHubMiddleware(cfg *config.Config) ([]httpclient.Middleware, *github.TokenPool, error) {
+	if cfg.GitHubAppID != 0 {
+		appAuth, err := github.NewAppAuthenticator(cfg.GitHubBaseURL, http.DefaultClient, cfg.GitHubAppID, cfg.GitHubAppPrivateKeyPath, cfg.GitHubAppInstallationID)
+		if err != nil {
+			return nil, nil, err
+		}
+		githubRateLimiter := github.NewGitHubRateLimiter()
+		return []httpclient.Middleware{githubRateLimiter.RateLimitMiddleware, httpclient.LoggingMiddleware, appAuth.AuthMiddleware}, nil, nil
+	}
+
+	tokens := cfg.GitHubTokens
+	if len(tokens) == 0 {
+		tokens = []string{cfg.GitHubToken}
+	}
+	tokenPool, err := github.NewTokenPool(tokens)
+	if err != nil {
+		return nil, nil, fmt.Errorf("set GITHUB_TOKEN or GITHUB_TOKENS: %w", err)
 	}
+	return []httpclient.Middleware{httpclient.LoggingMiddleware, tokenPool.Middleware}, tokenPool, nil
+}
+
+// NewExportContainer creates a container that only exports commits. It connects to the database
+// without running migrations or configuring GitHub access, so the export service is the only
+// one it provides.
+func NewExportContainer(cfg *config.Config) *Container {
+	dbConn, err := initializeDatabase(connectionString(cfg))
+	if err != nil {
+		panic(errors.Wrap(err, "Error connecting to database"))
+	}
+
+	return &Container{
+		cfg:           cfg,
+		dbConn:        dbConn,
+		exportService: services.NewExportService(postgresdb.NewRepositoryRepository(dbConn), postgresdb.NewCommitRepository(dbConn)),
+	}
+}
+
+// connectionString builds the connection string of the database.
+func connectionString(cfg *config.Config) string {
+	return fmt.Sprintf("postgresql://%s:%s@%s:5432/%s?sslmode=disable",
+		cfg.PostgresUser, cfg.PostgresPassword, cfg.PostgresHost, cfg.PostgresDB)
 }
 
 func initializeDatabase(connStr string) (*sqlx.DB, error) {
@@ -86,10 +168,40 @@ func initializeDatabase(connStr string) (*sqlx.DB, error) {
 	return dbConn, nil
 }
 
+// InitializeRepository reconciles the repositories with those declared in the repositories
+// file, or starts monitoring the default repository when there is no such file.
 func (c *Container) InitializeRepository() {
-	err := c.repoService.AddRepository(c.cfg.DefaultOwner, c.cfg.DefaultRepo)
-	if err != nil {
-		panic(fmt.Errorf("error initializing repository: %v", err))
+	if c.cfg.RepositoriesFile == "" {
+		err := c.repoService.AddRepository(c.cfg.DefaultOwner, c.cfg.DefaultRepo)
+		if err != nil {
+			panic(fmt.Errorf("error initializing repository: %v", err))
+		}
+		return
+	}
+
+	var declared []domain.Repository
+	var declaredOwners []string
+	for _, repository := range c.cfg.Repositories {
+		if repository.Name == "" {
+			declaredOwners = append(declaredOwners, repository.Owner)
+			continue
+		}
+		declared = append(declared, domain.Repository{
+			Owner:     repository.Owner,
+			Name:      repository.Name,
+			StartDate: repository.StartDate,
+			EndDate:   repository.EndDate,
+			Branches:  repository.Branches,
+			Schedule:  repository.Schedule,
+			Labels:    repository.Labels,
+		})
+	}
+
+	if err := c.repoService.Reconcile(context.Background(), declared); err != nil {
+		panic(fmt.Errorf("error reconciling repositories: %v", err))
+	}
+	if err := c.ownerService.Reconcile(context.Background(), declaredOwners); err != nil {
+		panic(fmt.Errorf("error reconciling owners: %v", err))
 	}
 }
 
@@ -101,9 +213,63 @@ func (c *Container) GetCommitService() services.CommitService {
 	return c.commitService
 }
 
+func (c *Container) GetWebhookService() services.WebhookService {
+	return c.webhookService
+}
+
+func (c *Container) GetOwnerService() services.OwnerService {
+	return c.ownerService
+}
+
+func (c *Container) GetEnrichmentService() services.EnrichmentService {
+	return c.enrichmentService
+}
+
+func (c *Container) GetExportService() services.ExportService {
+	return c.exportService
+}
+
+func (c *Container) GetContributorService() services.ContributorService {
+	return c.contributorService
+}
+
+// GetTokenPool returns the GitHub token pool, or nil when authenticating as a GitHub App.
+func (c *Container) GetTokenPool() *github.TokenPool {
+	return c.tokenPool
+}
+
 func (c *Container) StartServices() {
-	go c.commitService.CommitManager(c.monitoringChan, c.cfg.StartDate, c.cfg.EndDate)
+	go c.commitService.ResumeBackfills()
+	go c.commitService.CommitManager()
+	go c.repoService.RestoreMonitoring(context.Background(), c.commitChan) // Carry on monitoring the repositories added before the restart
 	go c.scheduler.ScheduleMonitoring(c.monitoringChan)
+	go c.scheduler.StopMonitoring(c.removalChan)
+	go c.scheduler.RescheduleMonitoring(c.rescheduleChan)
+	go c.ownerService.DiscoveryManager(c.cfg.DiscoveryInterval)
+	go c.enrichmentService.EnrichmentManager(c.cfg.EnrichmentInterval)
+	go c.initializeContributors()
+}
+
+// initializeContributors seeds the contributors of the commits stored before any were, then
+// imports the MAILMAP_FILE, if any.
+func (c *Container) initializeContributors() {
+	ctx := context.Background()
+	if _, err := c.contributorService.SeedContributors(ctx); err != nil {
+		logger.LogError(fmt.Errorf("error seeding contributors: %v", err))
+	}
+	if c.cfg.MailmapFile == "" {
+		return
+	}
+
+	file, err := os.Open(c.cfg.MailmapFile)
+	if err != nil {
+		logger.LogError(fmt.Errorf("error opening mailmap file: %v", err))
+		return
+	}
+	defer file.Close()
+	if _, err := c.contributorService.ImportMailmap(ctx, file); err != nil {
+		logger.LogError(fmt.Errorf("error importing mailmap file %s: %v", c.cfg.MailmapFile, err))
+	}
 }
 
 func (c *Container) Close() {
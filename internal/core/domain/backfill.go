@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Backfill job statuses.
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed"
+	BackfillStatusCancelled = "cancelled"
+)
+
+// BackfillJob tracks the progress of fetching a repository's commit history for a time window.
+// Tracked branches are collected one after the other. LastPage counts the pages of Branch whose
+// commits were saved and LastCommitDate is the oldest of them, so an interrupted job resumes
+// from that date, and CompletedBranches are skipped.
+type BackfillJob struct {
+	ID                int64          `db:"id" json:"id"`
+	RepositoryID      int64          `db:"repository_id" json:"repository_id"`
+	Since             string         `db:"since" json:"since,omitempty"`
+	Until             string         `db:"until" json:"until,omitempty"`
+	Branch            string         `db:"branch" json:"branch,omitempty"`
+	CompletedBranches pq.StringArray `db:"completed_branches" json:"completed_branches,omitempty"`
+	Status            string         `db:"status" json:"status"`
+	LastPage          int            `db:"last_page" json:"last_page"`
+	CommitsSaved      int            `db:"commits_saved" json:"commits_saved"`
+	LastCommitDate    *time.Time     `db:"last_commit_date" json:"last_commit_date,omitempty"`
+	Error             string         `db:"error" json:"error,omitempty"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+}
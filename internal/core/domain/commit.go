@@ -1,7 +1,14 @@
 package domain
 
-import "time"
+import (
+	"time"
 
+	"github.com/lib/pq"
+)
+
+// Commit is a commit of a monitored repository. The author wrote the change and the committer
+// applied it, such as GitHub for a squash merged from the web UI. CommitDate is the committer
+// date. Logins and IDs are those of the GitHub accounts matching the identities, if any.
 type Commit struct {
 	ID           int64     `db:"id" json:"-"`
 	RepositoryID int64     `db:"repository_id" json:"-"`
@@ -11,10 +18,45 @@ type Commit struct {
 	AuthorEmail  string    `db:"author_email" json:"author_email"`
 	CommitDate   time.Time `db:"commit_date" json:"commit_date"`
 	URL          string    `db:"url" json:"url"`
+
+	AuthorDate         *time.Time     `db:"author_date" json:"author_date,omitempty"`
+	AuthorLogin        string         `db:"author_login" json:"author_login,omitempty"`
+	AuthorID           *int64         `db:"author_id" json:"author_id,omitempty"`
+	CommitterName      string         `db:"committer_name" json:"committer_name"`
+	CommitterEmail     string         `db:"committer_email" json:"committer_email"`
+	CommitterLogin     string         `db:"committer_login" json:"committer_login,omitempty"`
+	CommitterID        *int64         `db:"committer_id" json:"committer_id,omitempty"`
+	ParentSHAs         pq.StringArray `db:"parent_shas" json:"parent_shas"`
+	TreeSHA            string         `db:"tree_sha" json:"tree_sha,omitempty"`
+	Verified           bool           `db:"verified" json:"verified"`
+	VerificationReason string         `db:"verification_reason" json:"verification_reason,omitempty"`
+	HTMLURL            string         `db:"html_url" json:"html_url,omitempty"`
+
+	// Line statistics are only known once the commit is enriched.
+	Additions    *int `db:"additions" json:"additions,omitempty"`
+	Deletions    *int `db:"deletions" json:"deletions,omitempty"`
+	TotalChanges *int `db:"total_changes" json:"total_changes,omitempty"`
+	// FilesTruncated is set when the commit changes more files than GitHub lists, so only some
+	// of its files are stored.
+	FilesTruncated bool `db:"files_truncated" json:"files_truncated,omitempty"`
+
+	// Unreachable is set once the commit is no longer on any tracked branch, such as after a
+	// force-push rewrote the history of the branch it was collected from.
+	Unreachable bool `db:"unreachable" json:"unreachable"`
+
+	// Branch the commit was collected from, if known. It is recorded as a branch membership.
+	Branch string `db:"-" json:"-"`
 }
 
 type CommitAuthor struct {
 	AuthorName  string `json:"author_name" db:"author_name"`
 	AuthorEmail string `json:"author_email" db:"author_email"`
 	CommitCount int    `json:"commit_count" db:"commit_count"`
+	// FirstCommitDate and LastCommitDate are the dates of the first and last commits counted.
+	FirstCommitDate time.Time `json:"first_commit_date" db:"first_commit_date"`
+	LastCommitDate  time.Time `json:"last_commit_date" db:"last_commit_date"`
+	// ActiveDays is the number of distinct days, in UTC, with commits counted.
+	ActiveDays int `json:"active_days" db:"active_days"`
+	// Repositories is the number of repositories with commits counted.
+	Repositories int `json:"repositories" db:"repositories"`
 }
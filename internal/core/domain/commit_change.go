@@ -0,0 +1,42 @@
+package domain
+
+// Enrichment statuses of a commit. Commits are stored pending and enriched with their line
+// statistics and changed files, unless they were stored before enrichment existed and were
+// skipped. Commits GitHub no longer knows fail.
+const (
+	EnrichmentStatusPending  = "pending"
+	EnrichmentStatusEnriched = "enriched"
+	EnrichmentStatusSkipped  = "skipped"
+	EnrichmentStatusFailed   = "failed"
+)
+
+// CommitChanges are the line statistics of a commit and the files it changed. FilesTruncated is
+// set when GitHub did not list all of the files. Metadata is the commit as GitHub reports it,
+// which fills in commits stored without their author date.
+type CommitChanges struct {
+	Additions      int
+	Deletions      int
+	TotalChanges   int
+	Files          []CommitFile
+	FilesTruncated bool
+	Metadata       Commit
+}
+
+// CommitFile is a file changed by a commit. PreviousFilename is set when the file was renamed.
+type CommitFile struct {
+	CommitID         int64  `db:"commit_id" json:"-"`
+	Path             string `db:"path" json:"path"`
+	Status           string `db:"status" json:"status"`
+	Additions        int    `db:"additions" json:"additions"`
+	Deletions        int    `db:"deletions" json:"deletions"`
+	PreviousFilename string `db:"previous_filename" json:"previous_filename,omitempty"`
+}
+
+// CommitRef identifies a stored commit along with its repository.
+type CommitRef struct {
+	ID           int64  `db:"id"`
+	RepositoryID int64  `db:"repository_id"`
+	Owner        string `db:"owner"`
+	Name         string `db:"name"`
+	Hash         string `db:"hash"`
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Commit sort orders, by commit date.
+const (
+	CommitSortNewest = "newest"
+	CommitSortOldest = "oldest"
+)
+
+// CommitFilter selects the commits of a repository. Empty fields do not filter.
+type CommitFilter struct {
+	// Branch the commits were collected from.
+	Branch string
+	// Author matches the name or email of the author, regardless of case.
+	Author string
+	// Since and Until bound the commit date, both inclusive.
+	Since *time.Time
+	Until *time.Time
+	// Query is searched in the messages with web search syntax, such as `fix -typo "memory leak"`.
+	Query string
+	// Path is a file or directory the commits changed. Only enriched commits are matched.
+	Path string
+	// Merge selects merge commits when true and the others when false.
+	Merge *bool
+	// Sort is CommitSortNewest or CommitSortOldest, newest first when empty.
+	Sort string
+}
+
+// Validate checks the filter and normalizes its path.
+func (f *CommitFilter) Validate() error {
+	if f.Sort != "" && f.Sort != CommitSortNewest && f.Sort != CommitSortOldest {
+		return fmt.Errorf("sort must be %q or %q", CommitSortNewest, CommitSortOldest)
+	}
+	if f.Since != nil && f.Until != nil && f.Until.Before(*f.Since) {
+		return fmt.Errorf("until must not be before since")
+	}
+	f.Path = strings.Trim(f.Path, "/")
+	return nil
+}
+
+// AuthorFilter selects the commits counted in an author leaderboard. Empty fields do not filter.
+type AuthorFilter struct {
+	// Owners and Labels restrict a leaderboard across repositories to the repositories of any of
+	// the owners carrying any of the labels.
+	Owners []string
+	Labels []string
+	// Since and Until bound the commit date, both inclusive.
+	Since *time.Time
+	Until *time.Time
+	// ExcludeBots leaves out the commits of GitHub Apps and other bots, whose login, name or
+	// noreply email ends in [bot].
+	ExcludeBots bool
+}
+
+// Validate checks the filter.
+func (f *AuthorFilter) Validate() error {
+	if f.Since != nil && f.Until != nil && f.Until.Before(*f.Since) {
+		return fmt.Errorf("until must not be before since")
+	}
+	return nil
+}
+
+// Commit export formats: comma separated values with a header row, or a JSON object per line.
+// Columnar formats such as Parquet are not offered: they buffer rows into column chunks rather
+// than streaming them one at a time.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// ValidateExportFormat checks that format is a commit export format.
+func ValidateExportFormat(format string) error {
+	if format != ExportFormatCSV && format != ExportFormatNDJSON {
+		return fmt.Errorf("format must be %q or %q", ExportFormatCSV, ExportFormatNDJSON)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Activity intervals, the length of the buckets commits are counted in.
+const (
+	ActivityIntervalDay   = "day"
+	ActivityIntervalWeek  = "week"
+	ActivityIntervalMonth = "month"
+)
+
+// MaxActivityBuckets bounds the number of buckets of an activity time series.
+const MaxActivityBuckets = 1000
+
+// ActivityQuery selects the commit activity of a repository between Since and Until, both
+// inclusive, in buckets of Interval. ByAuthor breaks each bucket down by author.
+type ActivityQuery struct {
+	Interval string
+	Since    *time.Time
+	Until    *time.Time
+	ByAuthor bool
+}
+
+// Normalize checks the query and fills in its defaults as of now: daily buckets over the last
+// 30 days, weekly ones over the last 26 weeks or monthly ones over the last 12 months.
+func (q *ActivityQuery) Normalize(now time.Time) error {
+	if q.Interval == "" {
+		q.Interval = ActivityIntervalDay
+	}
+	if q.Until == nil {
+		q.Until = &now
+	}
+
+	var since time.Time
+	switch q.Interval {
+	case ActivityIntervalDay:
+		since = q.Until.AddDate(0, 0, -30)
+	case ActivityIntervalWeek:
+		since = q.Until.AddDate(0, 0, -26*7)
+	case ActivityIntervalMonth:
+		since = q.Until.AddDate(-1, 0, 0)
+	default:
+		return fmt.Errorf("interval must be %q, %q or %q", ActivityIntervalDay, ActivityIntervalWeek, ActivityIntervalMonth)
+	}
+	if q.Since == nil {
+		q.Since = &since
+	}
+	if q.Until.Before(*q.Since) {
+		return fmt.Errorf("until must not be before since")
+	}
+
+	var buckets int
+	switch q.Interval {
+	case ActivityIntervalDay:
+		buckets = int(q.Until.Sub(*q.Since).Hours()/24) + 1
+	case ActivityIntervalWeek:
+		buckets = int(q.Until.Sub(*q.Since).Hours()/(24*7)) + 1
+	case ActivityIntervalMonth:
+		buckets = (q.Until.Year()-q.Since.Year())*12 + int(q.Until.Month()-q.Since.Month()) + 1
+	}
+	if buckets > MaxActivityBuckets {
+		return fmt.Errorf("the range spans more than %d %ss", MaxActivityBuckets, q.Interval)
+	}
+	return nil
+}
+
+// ActivityBucket counts the commits of a bucket starting at Start, in UTC.
+type ActivityBucket struct {
+	Start   time.Time        `db:"bucket" json:"start"`
+	Commits int              `db:"commits" json:"commits"`
+	Authors []AuthorActivity `db:"-" json:"authors,omitempty"`
+}
+
+// AuthorActivity counts the commits of an author in the bucket starting at Bucket.
+type AuthorActivity struct {
+	Bucket      time.Time `db:"bucket" json:"-"`
+	AuthorName  string    `db:"author_name" json:"author_name"`
+	AuthorEmail string    `db:"author_email" json:"author_email"`
+	Commits     int       `db:"commits" json:"commits"`
+}
+
+// PunchCardEntry counts the commits made on a weekday, from 0 for Sunday, at an hour in UTC.
+type PunchCardEntry struct {
+	Weekday int `db:"weekday" json:"weekday"`
+	Hour    int `db:"hour" json:"hour"`
+	Commits int `db:"commits" json:"commits"`
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Sources of contributor aliases. Manual and mailmap aliases take precedence over those seeded
+// from GitHub logins.
+const (
+	ContributorSourceGitHub  = "github"
+	ContributorSourceMailmap = "mailmap"
+	ContributorSourceManual  = "manual"
+)
+
+// Contributor is the canonical identity of a person authoring commits under the identities
+// matched by its aliases. Author statistics are aggregated by contributor, under its name and
+// email.
+type Contributor struct {
+	ID        int64              `db:"id" json:"id"`
+	Name      string             `db:"name" json:"name"`
+	Email     string             `db:"email" json:"email"`
+	Login     string             `db:"login" json:"login,omitempty"`
+	Aliases   []ContributorAlias `db:"-" json:"aliases"`
+	CreatedAt time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `db:"updated_at" json:"updated_at"`
+}
+
+// ContributorAlias matches the commits whose author has its name, email and GitHub login, empty
+// fields matching any. The login of an author without one is read from their noreply email,
+// such as 1234+jane@users.noreply.github.com.
+type ContributorAlias struct {
+	ID            int64     `db:"id" json:"id"`
+	ContributorID int64     `db:"contributor_id" json:"contributor_id"`
+	Name          string    `db:"name" json:"name,omitempty"`
+	Email         string    `db:"email" json:"email,omitempty"`
+	Login         string    `db:"login" json:"login,omitempty"`
+	Source        string    `db:"source" json:"source"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// Validate checks the contributor and its aliases and normalizes them.
+func (c *Contributor) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+	c.Login = strings.ToLower(strings.TrimSpace(c.Login))
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	for i := range c.Aliases {
+		if err := c.Aliases[i].Validate(); err != nil {
+			return fmt.Errorf("alias %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the alias has an email or a login and normalizes it, lowercasing them.
+func (a *ContributorAlias) Validate() error {
+	a.Name = strings.TrimSpace(a.Name)
+	a.Email = strings.ToLower(strings.TrimSpace(a.Email))
+	a.Login = strings.ToLower(strings.TrimSpace(a.Login))
+	if a.Email == "" && a.Login == "" {
+		return fmt.Errorf("email or login is required")
+	}
+	return nil
+}
+
+// MailmapEntry is a line of a .mailmap file: the commits of CommitEmail, and of CommitName when
+// given, are authored by ProperName and ProperEmail. An empty proper name or email keeps that of
+// the commits.
+type MailmapEntry struct {
+	ProperName  string
+	ProperEmail string
+	CommitName  string
+	CommitEmail string
+}
+
+// ParseMailmap reads the entries of a .mailmap file in any of the forms supported by git:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+//
+// Blank lines and comments starting with # are skipped. Emails are lowercased.
+func ParseMailmap(r io.Reader) ([]MailmapEntry, error) {
+	var entries []MailmapEntry
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry, err := parseMailmapLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseMailmapLine parses a line of a .mailmap file into its names and emails.
+func parseMailmapLine(line string) (MailmapEntry, error) {
+	var names, emails []string
+	rest := line
+	for len(emails) < 2 {
+		open := strings.IndexByte(rest, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[open:], '>')
+		if end < 0 {
+			return MailmapEntry{}, fmt.Errorf("unterminated email")
+		}
+		names = append(names, strings.TrimSpace(rest[:open]))
+		emails = append(emails, strings.ToLower(strings.TrimSpace(rest[open+1:open+end])))
+		rest = rest[open+end+1:]
+	}
+	if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+		return MailmapEntry{}, fmt.Errorf("unexpected %q after the emails", rest)
+	}
+
+	switch len(emails) {
+	case 1:
+		if names[0] == "" {
+			return MailmapEntry{}, fmt.Errorf("a single email needs a proper name")
+		}
+		if emails[0] == "" {
+			return MailmapEntry{}, fmt.Errorf("commit email is empty")
+		}
+		return MailmapEntry{ProperName: names[0], CommitEmail: emails[0]}, nil
+	case 2:
+		if emails[1] == "" {
+			return MailmapEntry{}, fmt.Errorf("commit email is empty")
+		}
+		return MailmapEntry{ProperName: names[0], ProperEmail: emails[0], CommitName: names[1], CommitEmail: emails[1]}, nil
+	default:
+		return MailmapEntry{}, fmt.Errorf("no email")
+	}
+}
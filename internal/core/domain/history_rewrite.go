@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// HistoryRewrite records that the history of a branch was rewritten, such as by a force-push,
+// so that commits collected from it are no longer on the branch. BeforeSHA is the latest commit
+// stored for the branch and AfterSHA its head once rewritten. MergeBaseSHA, the last commit the
+// two histories share, is empty when GitHub no longer knows BeforeSHA.
+type HistoryRewrite struct {
+	ID              int64     `db:"id" json:"id"`
+	RepositoryID    int64     `db:"repository_id" json:"repository_id"`
+	Branch          string    `db:"branch" json:"branch"`
+	BeforeSHA       string    `db:"before_sha" json:"before_sha"`
+	AfterSHA        string    `db:"after_sha" json:"after_sha"`
+	MergeBaseSHA    string    `db:"merge_base_sha" json:"merge_base_sha,omitempty"`
+	OrphanedCommits int       `db:"orphaned_commits" json:"orphaned_commits"`
+	DetectedAt      time.Time `db:"detected_at" json:"detected_at"`
+}
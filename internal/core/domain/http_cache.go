@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// HTTPCacheEntry holds the validators GitHub returned for a request URL so the
+// next request for the same URL can be made conditionally.
+type HTTPCacheEntry struct {
+	URL          string    `db:"url"`
+	ETag         string    `db:"etag"`
+	LastModified string    `db:"last_modified"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RepositoryFilter selects which repositories of an owner are monitored. Names are matched
+// against Include and Exclude globs such as "service-*", and languages case-insensitively.
+// Empty lists match every repository; archived repositories and forks are left out unless
+// included explicitly.
+type RepositoryFilter struct {
+	Include         pq.StringArray `db:"include" json:"include,omitempty"`
+	Exclude         pq.StringArray `db:"exclude" json:"exclude,omitempty"`
+	Languages       pq.StringArray `db:"languages" json:"languages,omitempty"`
+	IncludeArchived bool           `db:"include_archived" json:"include_archived"`
+	IncludeForks    bool           `db:"include_forks" json:"include_forks"`
+}
+
+// MonitoredOwner is a user or organization whose repositories are all monitored. Managed owners
+// are declared in the repositories file and stop being monitored once they are removed from it.
+type MonitoredOwner struct {
+	ID    int64  `db:"id" json:"id"`
+	Owner string `db:"owner" json:"owner"`
+	RepositoryFilter
+	Managed   bool      `db:"managed" json:"managed"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Validate checks that the name globs of the filter are well formed.
+func (f RepositoryFilter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a repository passes the filter.
+func (f RepositoryFilter) Matches(name, language string, archived, fork bool) bool {
+	if (archived && !f.IncludeArchived) || (fork && !f.IncludeForks) {
+		return false
+	}
+	if len(f.Include) > 0 && !matchesAny(f.Include, name) {
+		return false
+	}
+	if matchesAny(f.Exclude, name) {
+		return false
+	}
+	if len(f.Languages) == 0 {
+		return true
+	}
+	for _, l := range f.Languages {
+		if strings.EqualFold(l, language) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
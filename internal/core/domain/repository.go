@@ -1,6 +1,20 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Repository monitoring statuses. A stopped repository keeps its commits but is no longer
+// tracked; a paused one is still tracked but not polled until it is resumed.
+const (
+	RepositoryStatusActive  = "active"
+	RepositoryStatusPaused  = "paused"
+	RepositoryStatusStopped = "stopped"
+)
 
 type Repository struct {
 	ID              int64     `db:"id" json:"-"`
@@ -15,4 +29,65 @@ type Repository struct {
 	WatchersCount   int       `db:"watchers_count" json:"watchers_count"`
 	CreatedAt       time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+
+	MonitoringStatus string `db:"monitoring_status" json:"monitoring_status"`
+	Schedule         string `db:"schedule" json:"schedule,omitempty"`
+
+	// Adaptive polling state, see PollInterval.
+	PollIntervalSeconds int `db:"poll_interval_seconds" json:"poll_interval_seconds,omitempty"`
+	EmptyPolls          int `db:"empty_polls" json:"-"`
+
+	// Settings declared in the repositories file. Managed repositories stop being monitored
+	// once they are removed from it.
+	StartDate string         `db:"start_date" json:"start_date,omitempty"`
+	EndDate   string         `db:"end_date" json:"end_date,omitempty"`
+	Labels    pq.StringArray `db:"labels" json:"labels,omitempty"`
+	Managed   bool           `db:"managed" json:"managed"`
+
+	// Branches whose commits are collected, as names or globs such as "release/*". The default
+	// branch is collected when there are none.
+	Branches      pq.StringArray `db:"branches" json:"branches,omitempty"`
+	DefaultBranch string         `db:"default_branch" json:"default_branch,omitempty"`
+}
+
+// IsMonitored reports whether the repository has not been stopped.
+func (r *Repository) IsMonitored() bool {
+	return r.MonitoringStatus != RepositoryStatusStopped
+}
+
+// PollInterval returns the polling interval computed for the repository by adaptive polling,
+// zero when it has none.
+func (r *Repository) PollInterval() time.Duration {
+	return time.Duration(r.PollIntervalSeconds) * time.Second
+}
+
+// TracksBranch reports whether the commits of a branch are collected.
+func (r *Repository) TracksBranch(branch string) bool {
+	if len(r.Branches) == 0 {
+		return branch == r.DefaultBranch
+	}
+	for _, pattern := range r.Branches {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateBranchPatterns checks that branch names and globs are well formed.
+func ValidateBranchPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return fmt.Errorf("branch must not be empty")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsPaused reports whether polling of the repository is paused.
+func (r *Repository) IsPaused() bool {
+	return r.MonitoringStatus == RepositoryStatusPaused
 }
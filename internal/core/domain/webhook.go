@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Webhook delivery statuses.
+const (
+	WebhookStatusProcessing = "processing"
+	WebhookStatusProcessed  = "processed"
+	WebhookStatusIgnored    = "ignored"
+	WebhookStatusFailed     = "failed"
+)
+
+// WebhookDelivery records a single GitHub webhook delivery and the outcome of processing it.
+type WebhookDelivery struct {
+	ID          int64      `db:"id" json:"id"`
+	DeliveryID  string     `db:"delivery_id" json:"delivery_id"`
+	Event       string     `db:"event" json:"event"`
+	Status      string     `db:"status" json:"status"`
+	Error       string     `db:"error" json:"error,omitempty"`
+	Attempts    int        `db:"attempts" json:"attempts"`
+	Payload     string     `db:"payload" json:"-"`
+	ReceivedAt  time.Time  `db:"received_at" json:"received_at"`
+	ProcessedAt *time.Time `db:"processed_at" json:"processed_at,omitempty"`
+}
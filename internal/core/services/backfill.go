@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+var (
+	ErrJobNotFound        = stderrors.New("backfill job not found")
+	ErrJobNotRunning      = stderrors.New("backfill job is not running")
+	ErrJobNotRetryable    = stderrors.New("only failed or cancelled backfill jobs can be retried")
+	ErrBackfillInProgress = stderrors.New("repository already has an unfinished backfill")
+)
+
+// activeJob is a backfill job running in this process.
+type activeJob struct {
+	repositoryID int64
+	cancel       context.CancelFunc
+	done         chan struct{} // closed once the job has stopped
+}
+
+// StartBackfill starts fetching the commits of a repository for its backfill window in the
+// background. An unfinished backfill of the repository is resumed rather than started again.
+func (cs *commitService) StartBackfill(ctx context.Context, repoID int64) (*domain.BackfillJob, error) {
+	job, err := cs.backfillRepo.FindUnfinishedByRepositoryID(ctx, repoID)
+	if err != nil {
+		return nil, errors.New("FIND_BACKFILL_ERROR", "error finding unfinished backfill", err, errors.Critical)
+	}
+
+	if job == nil {
+		since, until, err := cs.backfillWindow(ctx, repoID)
+		if err != nil {
+			return nil, err
+		}
+		job, err = cs.createJob(ctx, repoID, since, until)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot := *job
+	cs.launch(job)
+	return &snapshot, nil
+}
+
+// ResumeBackfills restarts every backfill that was still running when the process stopped.
+func (cs *commitService) ResumeBackfills() {
+	jobs, err := cs.backfillRepo.FindUnfinished(context.Background())
+	if err != nil {
+		logger.LogError(errors.New("FIND_BACKFILL_ERROR", "error finding unfinished backfills", err, errors.Critical))
+		return
+	}
+
+	for i := range jobs {
+		logger.LogInfo(fmt.Sprintf("Resuming backfill job %d for repository ID %d after %d pages", jobs[i].ID, jobs[i].RepositoryID, jobs[i].LastPage))
+		cs.launch(&jobs[i])
+	}
+}
+
+// ProcessCommits fetches the commits of a job's window on every tracked branch it has not
+// completed yet, checkpointing after every page and branch, and hands the repository over to
+// monitoring once done. The branch being collected at the checkpoint is listed again up to the
+// oldest commit saved from it, as pages shift when commits are pushed in the meantime. Cancelling ctx stops the job and marks
+// it cancelled.
+func (cs *commitService) ProcessCommits(ctx context.Context, job *domain.BackfillJob) {
+	repository, err := getRepositoryByID(ctx, cs.repositoryService, job.RepositoryID)
+	if err == nil && repository == nil {
+		err = fmt.Errorf("repository ID %d not found", job.RepositoryID)
+	}
+	if err != nil {
+		logger.LogError(errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical))
+		cs.finishJob(ctx, job, domain.BackfillStatusFailed, err.Error())
+		return
+	}
+	owner, name := repository.Owner, repository.Name
+
+	completed := make(map[string]bool, len(job.CompletedBranches))
+	for _, branch := range job.CompletedBranches {
+		completed[branch] = true
+	}
+
+	branches, err := trackedBranches(ctx, cs.gitHubService, repository)
+	if i := slices.Index(branches, job.Branch); i > 0 {
+		// Carry on with the branch being collected at the checkpoint before the others.
+		branches = append([]string{job.Branch}, slices.Delete(branches, i, i+1)...)
+	}
+	for _, branch := range branches {
+		if err != nil {
+			break
+		}
+		if completed[branch] {
+			continue // collected before the checkpoint
+		}
+		if branch != job.Branch {
+			job.Branch, job.LastPage, job.LastCommitDate = branch, 0, nil
+		}
+
+		opts := github.CommitListOptions{Since: job.Since, Until: job.Until, Branch: branch}
+		if job.LastCommitDate != nil {
+			opts.Until = job.LastCommitDate.UTC().Format(time.RFC3339)
+		}
+		err = streamCommits(ctx, cs.gitHubService, owner, name, opts, job.RepositoryID, func(commits []domain.Commit) error {
+			if err := cs.SaveCommits(ctx, commits); err != nil {
+				return err
+			}
+
+			job.LastPage++
+			job.CommitsSaved += len(commits)
+			if oldest := oldestCommitDate(commits); oldest != nil {
+				job.LastCommitDate = oldest
+			}
+			return cs.backfillRepo.SaveCheckpoint(ctx, job.ID, job.Branch, job.CompletedBranches, job.LastPage, job.CommitsSaved, job.LastCommitDate)
+		})
+		if err == nil {
+			job.CompletedBranches = append(job.CompletedBranches, branch)
+			err = cs.backfillRepo.SaveCheckpoint(ctx, job.ID, job.Branch, job.CompletedBranches, job.LastPage, job.CommitsSaved, job.LastCommitDate)
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		logger.LogInfo(fmt.Sprintf("Backfill job %d for %s/%s cancelled after page %d", job.ID, owner, name, job.LastPage))
+		cs.finishJob(ctx, job, domain.BackfillStatusCancelled, "")
+	case err != nil:
+		logger.LogError(errors.New("FETCH_COMMITS_ERROR", "error fetching commits", err, errors.Critical))
+		cs.finishJob(ctx, job, domain.BackfillStatusFailed, err.Error())
+	default:
+		cs.finishJob(ctx, job, domain.BackfillStatusCompleted, "")
+		logger.LogInfo(fmt.Sprintf("Commits fetched successfully for %s/%s", owner, name))
+		cs.monitoringChan <- job.RepositoryID
+	}
+}
+
+// GetJob retrieves a backfill job with its latest checkpoint.
+func (cs *commitService) GetJob(ctx context.Context, jobID int64) (*domain.BackfillJob, error) {
+	job, err := cs.backfillRepo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, errors.New("GET_BACKFILL_ERROR", "error retrieving backfill job", err, errors.Critical)
+	}
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// CancelJob stops a running backfill job. The job records its cancellation once it has stopped.
+func (cs *commitService) CancelJob(ctx context.Context, jobID int64) error {
+	cs.mu.Lock()
+	active, running := cs.activeJobs[jobID]
+	cs.mu.Unlock()
+
+	if !running {
+		if _, err := cs.GetJob(ctx, jobID); err != nil {
+			return err
+		}
+		return ErrJobNotRunning
+	}
+
+	active.cancel()
+	logger.LogInfo(fmt.Sprintf("Cancellation requested for backfill job %d", jobID))
+	return nil
+}
+
+// RetryJob restarts a failed or cancelled backfill job from its last checkpoint.
+func (cs *commitService) RetryJob(ctx context.Context, jobID int64) (*domain.BackfillJob, error) {
+	job, err := cs.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.BackfillStatusFailed && job.Status != domain.BackfillStatusCancelled {
+		return nil, ErrJobNotRetryable
+	}
+
+	unfinished, err := cs.backfillRepo.FindUnfinishedByRepositoryID(ctx, job.RepositoryID)
+	if err != nil {
+		return nil, errors.New("FIND_BACKFILL_ERROR", "error finding unfinished backfill", err, errors.Critical)
+	}
+	if unfinished != nil {
+		return nil, ErrBackfillInProgress
+	}
+
+	if err := cs.backfillRepo.UpdateStatus(ctx, job.ID, domain.BackfillStatusRunning, ""); err != nil {
+		return nil, errors.New("UPDATE_BACKFILL_ERROR", "error restarting backfill job", err, errors.Critical)
+	}
+	job.Status = domain.BackfillStatusRunning
+	job.Error = ""
+
+	snapshot := *job
+	cs.launch(job)
+	return &snapshot, nil
+}
+
+// CancelBackfill cancels the unfinished backfill of a repository, if there is one, and waits
+// for it to stop, so that it saves no more commits once CancelBackfill returns.
+func (cs *commitService) CancelBackfill(ctx context.Context, repoID int64) error {
+	var stopping []chan struct{}
+	cs.mu.Lock()
+	for _, active := range cs.activeJobs {
+		if active.repositoryID == repoID {
+			active.cancel()
+			stopping = append(stopping, active.done)
+		}
+	}
+	cs.mu.Unlock()
+
+	for _, done := range stopping {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	job, err := cs.backfillRepo.FindUnfinishedByRepositoryID(ctx, repoID)
+	if err != nil || job == nil {
+		return err
+	}
+	return cs.backfillRepo.UpdateStatus(ctx, job.ID, domain.BackfillStatusCancelled, "")
+}
+
+// backfillWindow returns the window to backfill a repository over. Dates declared for the
+// repository take precedence over the configured ones.
+func (cs *commitService) backfillWindow(ctx context.Context, repoID int64) (string, string, error) {
+	repository, err := getRepositoryByID(ctx, cs.repositoryService, repoID)
+	if err != nil {
+		return "", "", errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical)
+	}
+
+	since, until := cs.startDate, cs.endDate
+	if repository != nil && repository.StartDate != "" {
+		since = repository.StartDate
+	}
+	if repository != nil && repository.EndDate != "" {
+		until = repository.EndDate
+	}
+	return since, until, nil
+}
+
+func (cs *commitService) createJob(ctx context.Context, repoID int64, since, until string) (*domain.BackfillJob, error) {
+	job := &domain.BackfillJob{
+		RepositoryID: repoID,
+		Since:        since,
+		Until:        until,
+		Status:       domain.BackfillStatusRunning,
+	}
+	if err := cs.backfillRepo.Create(ctx, job); err != nil {
+		return nil, errors.New("CREATE_BACKFILL_ERROR", "error creating backfill job", err, errors.Critical)
+	}
+	return job, nil
+}
+
+// launch runs a job in the background under a context that CancelJob can cancel. Being
+// background work, it waits out rate limits. Only one goroutine runs a given job at a time.
+func (cs *commitService) launch(job *domain.BackfillJob) {
+	ctx, cancel := context.WithCancel(github.WithRateLimitWait(context.Background()))
+	if !cs.claimJob(job, cancel) {
+		cancel()
+		logger.LogInfo(fmt.Sprintf("Backfill job %d is already running", job.ID))
+		return
+	}
+
+	go func() {
+		defer cs.releaseJob(job.ID)
+		defer cancel()
+		cs.ProcessCommits(ctx, job)
+	}()
+}
+
+// finishJob records the outcome of a job. It is recorded even when ctx has been cancelled.
+func (cs *commitService) finishJob(ctx context.Context, job *domain.BackfillJob, status, errMsg string) {
+	job.Status = status
+	job.Error = errMsg
+	if err := cs.backfillRepo.UpdateStatus(context.WithoutCancel(ctx), job.ID, status, errMsg); err != nil {
+		logger.LogError(errors.New("FINISH_BACKFILL_ERROR", "error recording backfill outcome", err, errors.Critical))
+	}
+}
+
+func (cs *commitService) claimJob(job *domain.BackfillJob, cancel context.CancelFunc) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if _, running := cs.activeJobs[job.ID]; running {
+		return false
+	}
+	cs.activeJobs[job.ID] = activeJob{repositoryID: job.RepositoryID, cancel: cancel, done: make(chan struct{})}
+	return true
+}
+
+func (cs *commitService) releaseJob(jobID int64) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if active, ok := cs.activeJobs[jobID]; ok {
+		close(active.done)
+		delete(cs.activeJobs, jobID)
+	}
+}
+
+// oldestCommitDate returns the earliest commit date in a page of commits.
+func oldestCommitDate(commits []domain.Commit) *time.Time {
+	var oldest *time.Time
+	for i := range commits {
+		if oldest == nil || commits[i].CommitDate.Before(*oldest) {
+			oldest = &commits[i].CommitDate
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+	date := *oldest
+	return &date
+}
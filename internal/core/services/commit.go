@@ -3,98 +3,105 @@ package services
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
 	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/pkg/errors"
 	"github.com/olusolaa/github-monitor/pkg/logger"
 	"github.com/olusolaa/github-monitor/pkg/pagination"
+	"github.com/olusolaa/github-monitor/pkg/utils"
 )
 
 type CommitService interface {
 	SaveCommits(ctx context.Context, commits []domain.Commit) error
 	GetLatestCommit(ctx context.Context, repoID int64) (*domain.Commit, error)
-	GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]domain.Commit, *pagination.Pagination, error)
-	ResetCollection(ctx context.Context, owner, name string, startTime time.Time) error
-	GetTopCommitAuthors(ctx context.Context, owner, name string, limit int) ([]domain.CommitAuthor, error)
-	CommitManager(monitoringChan chan int64, startDate, endDate string)
-	ProcessCommits(repoID int64, monitoringChan chan int64, startDate, endDate string)
+	GetLatestBranchCommit(ctx context.Context, repoID int64, branch string) (*domain.Commit, error)
+	CountRecentCommits(ctx context.Context, repoID int64, since time.Time) (int, error)
+	GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, *pagination.Pagination, error)
+	GetCommitsByCursor(ctx context.Context, owner, name string, filter domain.CommitFilter, params *pagination.CursorParams) ([]domain.Commit, *pagination.Cursors, error)
+	ResetCollection(ctx context.Context, owner, name string, startTime time.Time) (*domain.BackfillJob, error)
+	GetTopCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error)
+	GetTopCommitAuthorsByCursor(ctx context.Context, owner, name string, filter domain.AuthorFilter, params *pagination.CursorParams) ([]domain.CommitAuthor, *pagination.Cursors, error)
+	GetCommitActivity(ctx context.Context, owner, name string, query domain.ActivityQuery) ([]domain.ActivityBucket, error)
+	GetPunchCard(ctx context.Context, owner, name string, since, until *time.Time) ([]domain.PunchCardEntry, error)
+	RecordHistoryRewrite(ctx context.Context, rewrite *domain.HistoryRewrite, since time.Time) (*time.Time, error)
+	GetHistoryRewrites(ctx context.Context, owner, name string) ([]domain.HistoryRewrite, error)
+	CommitManager()
+	StartBackfill(ctx context.Context, repoID int64) (*domain.BackfillJob, error)
+	ProcessCommits(ctx context.Context, job *domain.BackfillJob)
+	ResumeBackfills()
+	GetJob(ctx context.Context, jobID int64) (*domain.BackfillJob, error)
+	CancelJob(ctx context.Context, jobID int64) error
+	RetryJob(ctx context.Context, jobID int64) (*domain.BackfillJob, error)
+	CancelBackfill(ctx context.Context, repoID int64) error
 }
 
 type commitService struct {
 	gitHubService     GitHubService
 	repositoryService RepositoryService
 	commitRepo        postgresdb.CommitRepository
+	backfillRepo      postgresdb.BackfillJobRepository
 	commitChan        chan int64
+	monitoringChan    chan int64
+	startDate         string
+	endDate           string
+
+	mu         sync.Mutex
+	activeJobs map[int64]activeJob
 }
 
-func NewCommitService(gitHubService GitHubService, repositoryService RepositoryService, commitRepo postgresdb.CommitRepository, commitChan chan int64) CommitService {
+// NewCommitService creates a commit service that backfills repositories received on commitChan
+// between startDate and endDate and hands them over to monitoringChan once done.
+func NewCommitService(gitHubService GitHubService, repositoryService RepositoryService, commitRepo postgresdb.CommitRepository, backfillRepo postgresdb.BackfillJobRepository, commitChan, monitoringChan chan int64, startDate, endDate string) CommitService {
 	return &commitService{
 		gitHubService:     gitHubService,
 		repositoryService: repositoryService,
 		commitRepo:        commitRepo,
+		backfillRepo:      backfillRepo,
 		commitChan:        commitChan,
+		monitoringChan:    monitoringChan,
+		startDate:         startDate,
+		endDate:           endDate,
+		activeJobs:        make(map[int64]activeJob),
 	}
 }
 
-func (cs *commitService) CommitManager(monitoringChan chan int64, startDate, endDate string) {
+// CommitManager collects the commits of the repositories received on commitChan.
+func (cs *commitService) CommitManager() {
 	for repoID := range cs.commitChan {
-		go cs.ProcessCommits(repoID, monitoringChan, startDate, endDate)
+		if err := cs.collectCommits(context.Background(), repoID); err != nil {
+			logger.LogError(err)
+		}
 	}
 }
 
-func (cs *commitService) ProcessCommits(repoID int64, monitoringChan chan int64, startDate, endDate string) {
-	ctx := context.Background()
-
-	owner, name, err := cs.repositoryService.GetOwnerAndRepoName(ctx, repoID)
+// collectCommits backfills a repository, resuming its unfinished backfill if it has one. A
+// repository whose backfill already completed is handed straight to monitoring instead, which
+// catches up incrementally from the latest stored commit. Commits stored without a completed
+// backfill, such as from push webhooks, do not count as collected.
+func (cs *commitService) collectCommits(ctx context.Context, repoID int64) error {
+	unfinished, err := cs.backfillRepo.FindUnfinishedByRepositoryID(ctx, repoID)
 	if err != nil {
-		logger.LogError(errors.New("GET_OWNER_REPO_NAME_ERROR", "error getting owner and repo name", err, errors.Critical))
-		return
+		return errors.New("FIND_BACKFILL_ERROR", "error finding unfinished backfill", err, errors.Critical)
 	}
 
-	var encounteredError error
-
-	domainCommitsChan := make(chan []domain.Commit)
-	errChan := make(chan error)
-
-	defer func() {
-		close(domainCommitsChan)
-		close(errChan)
-
-		if encounteredError != nil {
-			logger.LogError(errors.New("FETCH_COMMITS_ERROR", "error fetching commits", encounteredError, errors.Critical))
-		} else {
-			logger.LogInfo(fmt.Sprintf("Commits fetched successfully for %s/%s", owner, name))
-			monitoringChan <- repoID
+	if unfinished == nil {
+		completed, err := cs.backfillRepo.FindCompletedByRepositoryID(ctx, repoID)
+		if err != nil {
+			return errors.New("FIND_BACKFILL_ERROR", "error finding completed backfill", err, errors.Critical)
 		}
-	}()
-
-	go cs.gitHubService.FetchCommits(ctx, owner, name, startDate, endDate, repoID, domainCommitsChan, errChan)
-
-	for {
-		select {
-		case domainCommits, ok := <-domainCommitsChan:
-			if !ok {
-				encounteredError = errors.New("DOMAIN_COMMITS_CHANNEL_CLOSED", "domain commits channel closed unexpectedly", nil, errors.Critical)
-				return
-			}
-			if err := cs.SaveCommits(ctx, domainCommits); err != nil {
-				encounteredError = err
-				return
-			}
-		case err, ok := <-errChan:
-			if !ok {
-				encounteredError = errors.New("ERR_CHANNEL_CLOSED", "error channel closed unexpectedly", nil, errors.Critical)
-			} else if err != nil {
-				encounteredError = err
-			}
-			return
-		case <-ctx.Done():
-			encounteredError = errors.New("CONTEXT_DONE", "context canceled or timed out", ctx.Err(), errors.Critical)
-			return
+		if completed != nil {
+			logger.LogInfo(fmt.Sprintf("Repository ID %d was backfilled by job %d, catching up incrementally", repoID, completed.ID))
+			cs.monitoringChan <- repoID
+			return nil
 		}
 	}
+
+	_, err = cs.StartBackfill(ctx, repoID)
+	return err
 }
 
 // SaveCommits saves the provided commits into the repository
@@ -117,8 +124,33 @@ func (s *commitService) GetLatestCommit(ctx context.Context, repoID int64) (*dom
 	return latestCommit, nil
 }
 
-func (s *commitService) GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]domain.Commit, *pagination.Pagination, error) {
-	commits, totalItems, err := s.commitRepo.GetCommitsByRepositoryName(ctx, owner, name, page, pageSize)
+// CountRecentCommits counts the commits of a repository made since the given time.
+func (s *commitService) CountRecentCommits(ctx context.Context, repoID int64, since time.Time) (int, error) {
+	count, err := s.commitRepo.CountCommitsSince(ctx, repoID, since)
+	if err != nil {
+		logger.LogError(errors.New("COUNT_RECENT_COMMITS_ERROR", "error counting recent commits", err, errors.Critical))
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetLatestBranchCommit retrieves the most recent commit collected from a branch of a repository
+func (s *commitService) GetLatestBranchCommit(ctx context.Context, repoID int64, branch string) (*domain.Commit, error) {
+	latestCommit, err := s.commitRepo.GetLatestCommitByBranch(ctx, repoID, branch)
+	if err != nil {
+		logger.LogError(errors.New("GET_LATEST_COMMIT_ERROR", "error retrieving the latest commit of a branch", err, errors.Critical))
+		return nil, err
+	}
+	return latestCommit, nil
+}
+
+// GetCommitsByRepositoryName retrieves a page of the commits of a repository that match filter.
+func (s *commitService) GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, *pagination.Pagination, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, errors.New("INVALID_FILTER", "invalid commit filter", err, errors.Warning)
+	}
+
+	commits, totalItems, err := s.commitRepo.GetCommitsByRepositoryName(ctx, owner, name, filter, page, pageSize)
 	if err != nil {
 		logger.LogError(errors.New("GET_COMMITS_ERROR", "error retrieving commits", err, errors.Critical))
 		return nil, nil, err
@@ -129,8 +161,46 @@ func (s *commitService) GetCommitsByRepositoryName(ctx context.Context, owner, n
 	return commits, pg, nil
 }
 
-func (s *commitService) GetTopCommitAuthors(ctx context.Context, owner, name string, limit int) ([]domain.CommitAuthor, error) {
-	authors, err := s.commitRepo.GetTopCommitAuthors(ctx, owner, name, limit)
+// GetCommitsByCursor retrieves the page of the commits of a repository that match filter
+// following or preceding the cursor of params. The total is only counted when asked for.
+func (s *commitService) GetCommitsByCursor(ctx context.Context, owner, name string, filter domain.CommitFilter, params *pagination.CursorParams) ([]domain.Commit, *pagination.Cursors, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, errors.New("INVALID_FILTER", "invalid commit filter", err, errors.Warning)
+	}
+	if params.Cursor != nil && params.Cursor.ID == 0 {
+		return nil, nil, errors.New("INVALID_CURSOR", "invalid cursor", fmt.Errorf("cursor does not point to a commit"), errors.Warning)
+	}
+
+	rows, err := s.commitRepo.GetCommitsByCursor(ctx, owner, name, filter, params.Cursor, params.PageSize+1)
+	if err != nil {
+		logger.LogError(errors.New("GET_COMMITS_ERROR", "error retrieving commits", err, errors.Critical))
+		return nil, nil, err
+	}
+
+	commits, cursors := pagination.KeysetPage(rows, params, func(commit domain.Commit) pagination.Cursor {
+		return pagination.Cursor{Time: commit.CommitDate, ID: commit.ID}
+	})
+	if params.IncludeTotal {
+		total, err := s.commitRepo.CountCommits(ctx, owner, name, filter)
+		if err != nil {
+			logger.LogError(errors.New("COUNT_COMMITS_ERROR", "error counting commits", err, errors.Critical))
+			return nil, nil, err
+		}
+		cursors.TotalItems = &total
+	}
+
+	logger.LogInfo(fmt.Sprintf("Fetched %d commits for %s/%s", len(commits), owner, name))
+	return commits, cursors, nil
+}
+
+// GetTopCommitAuthors retrieves the limit authors with the most commits matching filter in a
+// repository, or across the repositories selected by filter when owner and name are empty.
+func (s *commitService) GetTopCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, errors.New("INVALID_FILTER", "invalid author filter", err, errors.Warning)
+	}
+
+	authors, err := s.commitRepo.GetTopCommitAuthors(ctx, owner, name, filter, limit)
 	if err != nil {
 		logger.LogError(errors.New("GET_TOP_AUTHORS_ERROR", "error retrieving top commit authors", err, errors.Critical))
 		return nil, err
@@ -139,11 +209,208 @@ func (s *commitService) GetTopCommitAuthors(ctx context.Context, owner, name str
 	return authors, nil
 }
 
-func (s *commitService) ResetCollection(ctx context.Context, owner, name string, startTime time.Time) error {
+// GetTopCommitAuthorsByCursor retrieves the page of the authors of the commits matching filter of
+// a repository, or of the repositories selected by filter when owner and name are empty, by
+// descending commit count, following or preceding the cursor of params. The total is only
+// counted when asked for.
+func (s *commitService) GetTopCommitAuthorsByCursor(ctx context.Context, owner, name string, filter domain.AuthorFilter, params *pagination.CursorParams) ([]domain.CommitAuthor, *pagination.Cursors, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, errors.New("INVALID_FILTER", "invalid author filter", err, errors.Warning)
+	}
+	if params.Cursor != nil && len(params.Cursor.Keys) != 2 {
+		return nil, nil, errors.New("INVALID_CURSOR", "invalid cursor", fmt.Errorf("cursor does not point to an author"), errors.Warning)
+	}
+
+	rows, err := s.commitRepo.GetTopCommitAuthorsByCursor(ctx, owner, name, filter, params.Cursor, params.PageSize+1)
+	if err != nil {
+		logger.LogError(errors.New("GET_TOP_AUTHORS_ERROR", "error retrieving top commit authors", err, errors.Critical))
+		return nil, nil, err
+	}
+
+	authors, cursors := pagination.KeysetPage(rows, params, func(author domain.CommitAuthor) pagination.Cursor {
+		return pagination.Cursor{Count: author.CommitCount, Keys: []string{author.AuthorName, author.AuthorEmail}}
+	})
+	if params.IncludeTotal {
+		total, err := s.commitRepo.CountCommitAuthors(ctx, owner, name, filter)
+		if err != nil {
+			logger.LogError(errors.New("COUNT_AUTHORS_ERROR", "error counting commit authors", err, errors.Critical))
+			return nil, nil, err
+		}
+		cursors.TotalItems = &total
+	}
+	return authors, cursors, nil
+}
+
+// GetCommitActivity counts the commits of a repository in the buckets selected by query, zero
+// for buckets without commits, and breaks them down by author when asked to.
+func (s *commitService) GetCommitActivity(ctx context.Context, owner, name string, query domain.ActivityQuery) ([]domain.ActivityBucket, error) {
+	if err := query.Normalize(time.Now().UTC()); err != nil {
+		return nil, errors.New("INVALID_ACTIVITY_QUERY", "invalid activity query", err, errors.Warning)
+	}
+	rep, err := s.repositoryService.GetRepository(ctx, name, owner)
+	if err != nil {
+		logger.LogError(errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical))
+		return nil, err
+	}
+	if rep == nil {
+		return nil, errors.New("REPOSITORY_NOT_FOUND", "repository is not monitored", fmt.Errorf("%s/%s not found", owner, name), errors.Warning)
+	}
+
+	buckets, err := s.commitRepo.GetCommitActivity(ctx, owner, name, query.Interval, *query.Since, *query.Until)
+	if err != nil {
+		logger.LogError(errors.New("GET_ACTIVITY_ERROR", "error retrieving commit activity", err, errors.Critical))
+		return nil, err
+	}
+	if !query.ByAuthor {
+		return buckets, nil
+	}
+
+	activity, err := s.commitRepo.GetCommitActivityByAuthor(ctx, owner, name, query.Interval, *query.Since, *query.Until)
+	if err != nil {
+		logger.LogError(errors.New("GET_ACTIVITY_ERROR", "error retrieving commit activity by author", err, errors.Critical))
+		return nil, err
+	}
+	// Both are ordered by bucket, and every bucket with authors is among the buckets.
+	i := 0
+	for _, author := range activity {
+		for i < len(buckets) && buckets[i].Start.Before(author.Bucket) {
+			i++
+		}
+		if i < len(buckets) && buckets[i].Start.Equal(author.Bucket) {
+			buckets[i].Authors = append(buckets[i].Authors, author)
+		}
+	}
+	return buckets, nil
+}
+
+// GetPunchCard counts the commits of a repository dated from since to until, over its whole
+// history when they are nil, by weekday and hour.
+func (s *commitService) GetPunchCard(ctx context.Context, owner, name string, since, until *time.Time) ([]domain.PunchCardEntry, error) {
+	from, to := time.Unix(0, 0).UTC(), time.Now().UTC()
+	if since != nil {
+		from = *since
+	}
+	if until != nil {
+		to = *until
+	}
+	if to.Before(from) {
+		return nil, errors.New("INVALID_ACTIVITY_QUERY", "invalid activity query", fmt.Errorf("until must not be before since"), errors.Warning)
+	}
+	rep, err := s.repositoryService.GetRepository(ctx, name, owner)
+	if err != nil {
+		logger.LogError(errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical))
+		return nil, err
+	}
+	if rep == nil {
+		return nil, errors.New("REPOSITORY_NOT_FOUND", "repository is not monitored", fmt.Errorf("%s/%s not found", owner, name), errors.Warning)
+	}
+
+	entries, err := s.commitRepo.GetPunchCard(ctx, owner, name, from, to)
+	if err != nil {
+		logger.LogError(errors.New("GET_PUNCH_CARD_ERROR", "error retrieving punch card", err, errors.Critical))
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RecordHistoryRewrite records that the history of a branch was rewritten. The branch is
+// collected again from since, the date of its merge base with its former history, and the
+// commits dated after since that it no longer contains are taken off it; those left on no branch
+// are marked unreachable. It returns the date of the oldest of them, nil when there are none. A
+// zero since means the branch has no merge base, and it is collected again from the start date of
+// its repository.
+func (s *commitService) RecordHistoryRewrite(ctx context.Context, rewrite *domain.HistoryRewrite, since time.Time) (*time.Time, error) {
+	since, reachable, err := s.recollectBranch(ctx, rewrite.RepositoryID, rewrite.Branch, since)
+	if err != nil {
+		logger.LogError(errors.New("RECOLLECT_BRANCH_ERROR", "error collecting rewritten branch again", err, errors.Critical))
+		return nil, err
+	}
+
+	oldest, err := s.commitRepo.RecordHistoryRewrite(ctx, rewrite, since, reachable)
+	if err != nil {
+		logger.LogError(errors.New("RECORD_HISTORY_REWRITE_ERROR", "error recording history rewrite", err, errors.Critical))
+		return nil, err
+	}
+	return oldest, nil
+}
+
+// recollectBranch saves the commits of a branch made since from, or since the start date of its
+// repository when from is zero, again. It returns the date they were collected from, zero when
+// from is zero and no start date is configured, and the hashes of the commits collected.
+func (s *commitService) recollectBranch(ctx context.Context, repoID int64, branch string, from time.Time) (time.Time, []string, error) {
+	repository, err := getRepositoryByID(ctx, s.repositoryService, repoID)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if repository == nil {
+		return time.Time{}, nil, fmt.Errorf("repository ID %d not found", repoID)
+	}
+
+	since := from.Format(time.RFC3339)
+	start := &from
+	if from.IsZero() {
+		since = s.startDate
+		if repository.StartDate != "" {
+			since = repository.StartDate
+		}
+		if start, err = utils.ParseDate(since, false); err != nil {
+			return time.Time{}, nil, fmt.Errorf("invalid start date %q: %w", since, err)
+		}
+	}
+
+	var hashes []string
+	opts := github.CommitListOptions{Since: since, Branch: branch}
+	err = streamCommits(ctx, s.gitHubService, repository.Owner, repository.Name, opts, repoID, func(commits []domain.Commit) error {
+		if err := s.SaveCommits(ctx, commits); err != nil {
+			return err
+		}
+		for _, commit := range commits {
+			hashes = append(hashes, commit.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	if start == nil {
+		return time.Time{}, hashes, nil
+	}
+	return *start, hashes, nil
+}
+
+// GetHistoryRewrites retrieves the history rewrites detected for a repository, latest first.
+func (s *commitService) GetHistoryRewrites(ctx context.Context, owner, name string) ([]domain.HistoryRewrite, error) {
+	rewrites, err := s.commitRepo.GetHistoryRewrites(ctx, owner, name)
+	if err != nil {
+		logger.LogError(errors.New("GET_HISTORY_REWRITES_ERROR", "error retrieving history rewrites", err, errors.Critical))
+		return nil, err
+	}
+	return rewrites, nil
+}
+
+// ResetCollection deletes the stored commits of a repository and starts a backfill of its
+// commits since startTime, cancelling any unfinished backfill of the repository first and
+// waiting for it to stop.
+func (s *commitService) ResetCollection(ctx context.Context, owner, name string, startTime time.Time) (*domain.BackfillJob, error) {
+	rep, err := s.repositoryService.GetRepository(ctx, name, owner)
+	if err != nil {
+		logger.LogError(errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical))
+		return nil, err
+	}
+	if rep == nil {
+		return nil, errors.New("REPOSITORY_NOT_FOUND", "repository is not monitored", fmt.Errorf("%s/%s not found", owner, name), errors.Warning)
+	}
+
+	if err := s.CancelBackfill(ctx, rep.ID); err != nil {
+		logger.LogError(errors.New("CANCEL_BACKFILL_ERROR", "error cancelling unfinished backfill", err, errors.Critical))
+		return nil, err
+	}
+
 	tx, err := s.commitRepo.BeginTx(ctx)
 	if err != nil {
 		logger.LogError(errors.New("BEGIN_TRANSACTION_ERROR", "error beginning transaction", err, errors.Critical))
-		return err
+		return nil, err
 	}
 
 	defer func() {
@@ -153,61 +420,25 @@ func (s *commitService) ResetCollection(ctx context.Context, owner, name string,
 		}
 	}()
 
-	rep, err := s.repositoryService.GetRepository(ctx, name, owner)
+	err = s.commitRepo.DeleteCommitsByRepositoryID(ctx, rep.ID)
 	if err != nil {
 		logger.LogError(errors.New("DELETE_COMMITS_ERROR", "error deleting commits", err, errors.Critical))
 		tx.Rollback()
-		return err
+		return nil, err
 	}
+	if err := tx.Commit(); err != nil {
+		logger.LogError(errors.New("COMMIT_TRANSACTION_ERROR", "error committing transaction", err, errors.Critical))
+		return nil, err
+	}
+	logger.LogInfo(fmt.Sprintf("Collection reset successfully for repository name: %s", name))
 
-	startTimeStr := startTime.Format(time.RFC3339)
-	err = s.commitRepo.DeleteCommitsByRepositoryID(ctx, rep.ID)
+	job, err := s.createJob(ctx, rep.ID, startTime.Format(time.RFC3339), "")
 	if err != nil {
-		logger.LogError(errors.New("GET_OWNER_REPO_NAME_ERROR", "error getting owner and repo name", err, errors.Critical))
-		tx.Rollback()
-		return err
-	} else {
-		if err := tx.Commit(); err != nil {
-			logger.LogError(errors.New("COMMIT_TRANSACTION_ERROR", "error committing transaction", err, errors.Critical))
-			return err
-		}
-		logger.LogInfo(fmt.Sprintf("Collection reset successfully for repository name: %s", name))
+		logger.LogError(err)
+		return nil, err
 	}
 
-	domainCommitsChan := make(chan []domain.Commit)
-	errChan := make(chan error)
-
-	defer func() {
-		close(domainCommitsChan)
-		close(errChan)
-	}()
-
-	go s.gitHubService.FetchCommits(ctx, rep.Owner, rep.Name, startTimeStr, "", rep.ID, domainCommitsChan, errChan)
-
-	for {
-		select {
-		case domainCommits, ok := <-domainCommitsChan:
-			if !ok {
-				return errors.New("DOMAIN_COMMITS_CHANNEL_CLOSED", "domain commits channel closed unexpectedly", nil, errors.Critical)
-			}
-			if err := s.SaveCommits(ctx, domainCommits); err != nil {
-				logger.LogError(errors.New("SAVE_COMMITS_ERROR", "error saving commits", err, errors.Critical))
-				tx.Rollback()
-				return err
-			}
-		case err, ok := <-errChan:
-			if !ok {
-				return errors.New("ERR_CHANNEL_CLOSED", "error channel closed unexpectedly", nil, errors.Critical)
-			}
-			if err != nil {
-				logger.LogError(errors.New("FETCH_COMMITS_ERROR", "error fetching commits", err, errors.Critical))
-				tx.Rollback()
-			}
-			return err
-		case <-ctx.Done():
-			logger.LogError(errors.New("CONTEXT_DONE", "context canceled or timed out", ctx.Err(), errors.Critical))
-			tx.Rollback()
-			return ctx.Err()
-		}
-	}
+	snapshot := *job
+	s.launch(job)
+	return &snapshot, nil
 }
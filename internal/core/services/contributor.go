@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+type ContributorService interface {
+	ListContributors(ctx context.Context) ([]domain.Contributor, error)
+	GetContributor(ctx context.Context, id int64) (*domain.Contributor, error)
+	CreateContributor(ctx context.Context, contributor *domain.Contributor) error
+	UpdateContributor(ctx context.Context, contributor *domain.Contributor) error
+	DeleteContributor(ctx context.Context, id int64) error
+	MergeContributors(ctx context.Context, id, mergedID int64) (*domain.Contributor, error)
+	AddAlias(ctx context.Context, alias *domain.ContributorAlias) error
+	DeleteAlias(ctx context.Context, contributorID, aliasID int64) error
+	ImportMailmap(ctx context.Context, r io.Reader) (int, error)
+	SeedContributors(ctx context.Context) (int, error)
+}
+
+type contributorService struct {
+	contributorRepo postgresdb.ContributorRepository
+}
+
+// NewContributorService creates a service managing the canonical identities commit authors are
+// aggregated by.
+func NewContributorService(contributorRepo postgresdb.ContributorRepository) ContributorService {
+	return &contributorService{contributorRepo: contributorRepo}
+}
+
+// ListContributors retrieves every contributor with its aliases.
+func (s *contributorService) ListContributors(ctx context.Context) ([]domain.Contributor, error) {
+	contributors, err := s.contributorRepo.FindAll(ctx)
+	if err != nil {
+		logger.LogError(err)
+		return nil, errors.New("GET_CONTRIBUTORS_ERROR", "error getting contributors", err, errors.Critical)
+	}
+	return contributors, nil
+}
+
+// GetContributor retrieves a contributor with its aliases.
+func (s *contributorService) GetContributor(ctx context.Context, id int64) (*domain.Contributor, error) {
+	contributor, err := s.contributorRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.LogError(err)
+		return nil, errors.New("GET_CONTRIBUTOR_ERROR", "error getting contributor", err, errors.Critical)
+	}
+	if contributor == nil {
+		return nil, errors.New("CONTRIBUTOR_NOT_FOUND", "contributor not found", fmt.Errorf("contributor %d not found", id), errors.Warning)
+	}
+	return contributor, nil
+}
+
+// CreateContributor creates a contributor with its aliases, which are taken over from the
+// contributors that have them already, and gives it the commits they match. A contributor with
+// a login is given an alias of it, so that the commits of the login are its own.
+func (s *contributorService) CreateContributor(ctx context.Context, contributor *domain.Contributor) error {
+	if err := contributor.Validate(); err != nil {
+		return errors.New("INVALID_CONTRIBUTOR", "invalid contributor", err, errors.Warning)
+	}
+	loginAlias := domain.ContributorAlias{Login: contributor.Login}
+	if contributor.Login != "" && !slices.Contains(contributor.Aliases, loginAlias) {
+		contributor.Aliases = append(contributor.Aliases, loginAlias)
+	}
+	for i := range contributor.Aliases {
+		contributor.Aliases[i].Source = domain.ContributorSourceManual
+	}
+
+	if err := s.contributorRepo.Create(ctx, contributor); err != nil {
+		return saveContributorError(err)
+	}
+	logger.LogInfo(fmt.Sprintf("Created contributor %d (%s)", contributor.ID, contributor.Name))
+	return nil
+}
+
+// UpdateContributor sets the name, email and login of a contributor, under which its commits are
+// aggregated. The commits of its login become its own, and those of a former login only when
+// another alias matches them. The contributor is updated with its stored aliases.
+func (s *contributorService) UpdateContributor(ctx context.Context, contributor *domain.Contributor) error {
+	if _, err := s.GetContributor(ctx, contributor.ID); err != nil {
+		return err
+	}
+	contributor.Aliases = nil
+	if err := contributor.Validate(); err != nil {
+		return errors.New("INVALID_CONTRIBUTOR", "invalid contributor", err, errors.Warning)
+	}
+
+	if err := s.contributorRepo.Update(ctx, contributor); err != nil {
+		return saveContributorError(err)
+	}
+	updated, err := s.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		return err
+	}
+	*contributor = *updated
+	return nil
+}
+
+// DeleteContributor deletes a contributor. Its commits are resolved again to the aliases of the
+// other contributors, or left without one. A contributor seeded from a GitHub login is seeded
+// anew on the next start, so its commits are only given to another contributor by merging it.
+func (s *contributorService) DeleteContributor(ctx context.Context, id int64) error {
+	if _, err := s.GetContributor(ctx, id); err != nil {
+		return err
+	}
+	if err := s.contributorRepo.Delete(ctx, id); err != nil {
+		logger.LogError(err)
+		return errors.New("DELETE_CONTRIBUTOR_ERROR", "error deleting contributor", err, errors.Critical)
+	}
+	logger.LogInfo(fmt.Sprintf("Deleted contributor %d", id))
+	return nil
+}
+
+// MergeContributors merges the contributor mergedID, its aliases and commits, into the
+// contributor id and returns the merged contributor.
+func (s *contributorService) MergeContributors(ctx context.Context, id, mergedID int64) (*domain.Contributor, error) {
+	if id == mergedID {
+		return nil, errors.New("INVALID_MERGE", "invalid merge", fmt.Errorf("a contributor cannot be merged into itself"), errors.Warning)
+	}
+	if _, err := s.GetContributor(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetContributor(ctx, mergedID); err != nil {
+		return nil, err
+	}
+
+	if err := s.contributorRepo.Merge(ctx, id, mergedID); err != nil {
+		logger.LogError(err)
+		return nil, errors.New("MERGE_CONTRIBUTORS_ERROR", "error merging contributors", err, errors.Critical)
+	}
+	logger.LogInfo(fmt.Sprintf("Merged contributor %d into %d", mergedID, id))
+	return s.GetContributor(ctx, id)
+}
+
+// AddAlias gives an alias to a contributor, taking it over from the contributor that has it
+// already, and gives the contributor the commits it matches.
+func (s *contributorService) AddAlias(ctx context.Context, alias *domain.ContributorAlias) error {
+	if _, err := s.GetContributor(ctx, alias.ContributorID); err != nil {
+		return err
+	}
+	if err := alias.Validate(); err != nil {
+		return errors.New("INVALID_ALIAS", "invalid contributor alias", err, errors.Warning)
+	}
+	alias.Source = domain.ContributorSourceManual
+
+	if err := s.contributorRepo.SaveAlias(ctx, alias); err != nil {
+		return saveContributorError(err)
+	}
+	return nil
+}
+
+// DeleteAlias deletes an alias of a contributor, whose commits are resolved again.
+func (s *contributorService) DeleteAlias(ctx context.Context, contributorID, aliasID int64) error {
+	deleted, err := s.contributorRepo.DeleteAlias(ctx, contributorID, aliasID)
+	if err != nil {
+		logger.LogError(err)
+		return errors.New("DELETE_ALIAS_ERROR", "error deleting contributor alias", err, errors.Critical)
+	}
+	if !deleted {
+		return errors.New("ALIAS_NOT_FOUND", "contributor alias not found", fmt.Errorf("alias %d of contributor %d not found", aliasID, contributorID), errors.Warning)
+	}
+	return nil
+}
+
+// ImportMailmap imports the entries of a .mailmap file and returns how many there were. Each
+// entry gives the commits it matches to the contributor with its proper email, created when
+// there is none, and named after its proper name.
+func (s *contributorService) ImportMailmap(ctx context.Context, r io.Reader) (int, error) {
+	entries, err := domain.ParseMailmap(r)
+	if err != nil {
+		return 0, errors.New("INVALID_MAILMAP", "invalid mailmap", err, errors.Warning)
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	if err := s.contributorRepo.ImportMailmap(ctx, entries); err != nil {
+		logger.LogError(err)
+		return 0, errors.New("IMPORT_MAILMAP_ERROR", "error importing mailmap", err, errors.Critical)
+	}
+	logger.LogInfo(fmt.Sprintf("Imported %d mailmap entries", len(entries)))
+	return len(entries), nil
+}
+
+// SeedContributors creates a contributor for every GitHub login, or login in a noreply email, of
+// the commits without a contributor yet, such as those stored before contributors existed, and
+// resolves those commits. It returns how many contributors were created.
+func (s *contributorService) SeedContributors(ctx context.Context) (int, error) {
+	created, err := s.contributorRepo.Seed(ctx)
+	if err != nil {
+		logger.LogError(err)
+		return 0, errors.New("SEED_CONTRIBUTORS_ERROR", "error seeding contributors", err, errors.Critical)
+	}
+	if created > 0 {
+		logger.LogInfo(fmt.Sprintf("Seeded %d contributors", created))
+	}
+	return created, nil
+}
+
+// saveContributorError reports a contributor or alias that could not be saved, as a warning when
+// it conflicts with the login of another contributor.
+func saveContributorError(err error) error {
+	if stderrors.Is(err, postgresdb.ErrConflict) {
+		return errors.New("CONTRIBUTOR_CONFLICT", "contributor login is already taken", err, errors.Warning)
+	}
+	logger.LogError(err)
+	return errors.New("SAVE_CONTRIBUTOR_ERROR", "error saving contributor", err, errors.Critical)
+}
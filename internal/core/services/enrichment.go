@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// enrichmentBatchSize is how many pending commits an enrichment pass loads at a time.
+const enrichmentBatchSize = 100
+
+// maxEnrichmentAttempts is how many times enriching a commit may fail before it is marked failed.
+const maxEnrichmentAttempts = 3
+
+type EnrichmentService interface {
+	EnrichPending(ctx context.Context) (int, error)
+	RequestEnrichment(ctx context.Context, owner, name string) (int, error)
+	GetCommitFiles(ctx context.Context, owner, name, sha string) ([]domain.CommitFile, error)
+	EnrichmentManager(interval time.Duration)
+}
+
+type enrichmentService struct {
+	gitHubService     GitHubService
+	repositoryService RepositoryService
+	changeRepo        postgresdb.CommitChangeRepository
+	concurrency       int
+
+	// passMu keeps a single enrichment pass running at a time.
+	passMu sync.Mutex
+}
+
+// NewEnrichmentService creates a service fetching the line statistics and changed files of
+// commits, at most concurrency at a time.
+func NewEnrichmentService(gitHubService GitHubService, repositoryService RepositoryService, changeRepo postgresdb.CommitChangeRepository, concurrency int) EnrichmentService {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &enrichmentService{
+		gitHubService:     gitHubService,
+		repositoryService: repositoryService,
+		changeRepo:        changeRepo,
+		concurrency:       concurrency,
+	}
+}
+
+// EnrichPending enriches the commits waiting to be enriched, newest first, until none are left
+// and returns how many were enriched. Commits GitHub no longer knows are marked failed. Any
+// other error is logged and the commit is left pending for the next pass, until it has failed
+// maxEnrichmentAttempts times and is marked failed too. The pass ends early when ctx is done.
+func (s *enrichmentService) EnrichPending(ctx context.Context) (int, error) {
+	s.passMu.Lock()
+	defer s.passMu.Unlock()
+	return s.enrichPending(ctx)
+}
+
+func (s *enrichmentService) enrichPending(ctx context.Context) (int, error) {
+	enriched := 0
+	// Commits that failed are only attempted once per pass.
+	var failedIDs []int64
+	for {
+		refs, err := s.changeRepo.FindPending(ctx, enrichmentBatchSize, failedIDs)
+		if err != nil {
+			return enriched, errors.New("FIND_PENDING_ENRICHMENT_ERROR", "error finding commits to enrich", err, errors.Critical)
+		}
+		if len(refs) == 0 {
+			return enriched, nil
+		}
+
+		count, failed, err := s.enrichBatch(ctx, refs)
+		enriched += count
+		failedIDs = append(failedIDs, failed...)
+		if err != nil {
+			return enriched, err
+		}
+	}
+}
+
+// enrichBatch enriches refs over a bounded number of workers and returns how many were enriched
+// along with the IDs of those that failed. It stops when ctx is done or a failure cannot be
+// recorded.
+func (s *enrichmentService) enrichBatch(ctx context.Context, refs []domain.CommitRef) (int, []int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		enriched  int
+		failedIDs []int64
+		firstErr  error
+	)
+	refsChan := make(chan domain.CommitRef)
+	for i := 0; i < s.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range refsChan {
+				ok, err := s.enrichCommit(ctx, ref)
+				failed := err != nil && ctx.Err() == nil
+				if failed {
+					err = s.recordFailure(ctx, ref, err)
+				}
+				mu.Lock()
+				if ok {
+					enriched++
+				}
+				if failed {
+					failedIDs = append(failedIDs, ref.ID)
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, ref := range refs {
+		select {
+		case refsChan <- ref:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(refsChan)
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	return enriched, failedIDs, firstErr
+}
+
+// recordFailure logs why a commit could not be enriched and records the failed attempt. Only an
+// error recording the attempt is returned.
+func (s *enrichmentService) recordFailure(ctx context.Context, ref domain.CommitRef, cause error) error {
+	logger.LogError(fmt.Errorf("failed to enrich commit %s of %s/%s: %w", ref.Hash, ref.Owner, ref.Name, cause))
+	failed, err := s.changeRepo.RecordFailure(ctx, ref.ID, maxEnrichmentAttempts)
+	if err != nil {
+		return errors.New("RECORD_ENRICHMENT_FAILURE_ERROR", "error recording enrichment failure", err, errors.Critical)
+	}
+	if failed {
+		logger.LogWarning(fmt.Sprintf("Commit %s of %s/%s failed to be enriched %d times, it is not enriched", ref.Hash, ref.Owner, ref.Name, maxEnrichmentAttempts))
+	}
+	return nil
+}
+
+// enrichCommit fetches and stores the changes of a commit, reporting whether it was enriched.
+func (s *enrichmentService) enrichCommit(ctx context.Context, ref domain.CommitRef) (bool, error) {
+	changes, err := s.gitHubService.FetchCommitChanges(ctx, ref.Owner, ref.Name, ref.Hash)
+	if stderrors.Is(err, github.ErrNotFound) {
+		logger.LogWarning(fmt.Sprintf("Commit %s of %s/%s no longer exists, it is not enriched", ref.Hash, ref.Owner, ref.Name))
+		if err := s.changeRepo.UpdateStatus(ctx, ref.ID, domain.EnrichmentStatusFailed); err != nil {
+			return false, errors.New("UPDATE_ENRICHMENT_STATUS_ERROR", "error updating enrichment status", err, errors.Critical)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.New("FETCH_COMMIT_CHANGES_ERROR", "error fetching commit changes", err, errors.Critical)
+	}
+
+	if err := s.changeRepo.Save(ctx, ref.ID, changes); err != nil {
+		return false, errors.New("SAVE_COMMIT_CHANGES_ERROR", "error saving commit changes", err, errors.Critical)
+	}
+	return true, nil
+}
+
+// RequestEnrichment queues the commits of a repository that were skipped or failed for
+// enrichment and starts enriching them in the background. It returns how many were queued.
+func (s *enrichmentService) RequestEnrichment(ctx context.Context, owner, name string) (int, error) {
+	rep, err := s.repositoryService.GetRepository(ctx, name, owner)
+	if err != nil {
+		logger.LogError(errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical))
+		return 0, err
+	}
+	if rep == nil {
+		return 0, errors.New("REPOSITORY_NOT_FOUND", "repository is not monitored", fmt.Errorf("%s/%s not found", owner, name), errors.Warning)
+	}
+
+	queued, err := s.changeRepo.MarkPending(ctx, rep.ID)
+	if err != nil {
+		logger.LogError(err)
+		return 0, errors.New("QUEUE_ENRICHMENT_ERROR", "error queueing commits for enrichment", err, errors.Critical)
+	}
+
+	go s.runPass()
+	logger.LogInfo(fmt.Sprintf("Queued %d commits of %s/%s for enrichment", queued, owner, name))
+	return queued, nil
+}
+
+// GetCommitFiles retrieves the files changed by a commit, which are empty until it is enriched.
+func (s *enrichmentService) GetCommitFiles(ctx context.Context, owner, name, sha string) ([]domain.CommitFile, error) {
+	files, err := s.changeRepo.FindFiles(ctx, owner, name, sha)
+	if err != nil {
+		logger.LogError(err)
+		return nil, errors.New("GET_COMMIT_FILES_ERROR", "error getting commit files", err, errors.Critical)
+	}
+	return files, nil
+}
+
+// EnrichmentManager enriches the commits stored since the last pass every interval.
+// A non-positive interval disables the enrichment of new commits.
+func (s *enrichmentService) EnrichmentManager(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.runPass()
+	}
+}
+
+// runPass runs an enrichment pass unless one is already running, which picks up newly pending
+// commits by itself. Being background work, it waits out rate limits.
+func (s *enrichmentService) runPass() {
+	if !s.passMu.TryLock() {
+		return
+	}
+	defer s.passMu.Unlock()
+
+	enriched, err := s.enrichPending(github.WithRateLimitWait(context.Background()))
+	if err != nil {
+		logger.LogError(err)
+	}
+	if enriched > 0 {
+		logger.LogInfo(fmt.Sprintf("Enriched %d commits", enriched))
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+type ExportService interface {
+	ExportCommits(ctx context.Context, owner, name string, filter domain.CommitFilter, format string, w io.Writer) (int, error)
+}
+
+type exportService struct {
+	repoRepo   postgresdb.RepositoryRepository
+	commitRepo postgresdb.CommitRepository
+}
+
+// NewExportService creates a service exporting the commits of repositories in bulk. It only
+// reads the database, so it can run without GitHub access.
+func NewExportService(repoRepo postgresdb.RepositoryRepository, commitRepo postgresdb.CommitRepository) ExportService {
+	return &exportService{
+		repoRepo:   repoRepo,
+		commitRepo: commitRepo,
+	}
+}
+
+// flusher is implemented by writers that can send buffered data to the client, such as an
+// http.ResponseWriter.
+type flusher interface {
+	Flush()
+}
+
+// ExportCommits writes the commits of a repository that match filter to w in format, streaming
+// them from the database a batch at a time, and returns how many were written. Nothing is
+// written when the format, filter or repository is invalid.
+func (s *exportService) ExportCommits(ctx context.Context, owner, name string, filter domain.CommitFilter, format string, w io.Writer) (int, error) {
+	if err := domain.ValidateExportFormat(format); err != nil {
+		return 0, errors.New("INVALID_FORMAT", "invalid export format", err, errors.Warning)
+	}
+	if err := filter.Validate(); err != nil {
+		return 0, errors.New("INVALID_FILTER", "invalid commit filter", err, errors.Warning)
+	}
+
+	rep, err := s.repoRepo.FindByNameAndOwner(ctx, name, owner)
+	if err != nil {
+		logger.LogError(errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical))
+		return 0, err
+	}
+	if rep == nil {
+		return 0, errors.New("REPOSITORY_NOT_FOUND", "repository is not monitored", fmt.Errorf("%s/%s not found", owner, name), errors.Warning)
+	}
+
+	writer := newCommitWriter(format, w)
+	exported := 0
+	err = s.commitRepo.StreamCommits(ctx, owner, name, filter, func(commits []domain.Commit) error {
+		if err := writer.Write(commits); err != nil {
+			return fmt.Errorf("failed to write commits: %w", err)
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write commits: %w", err)
+		}
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+		exported += len(commits)
+		return nil
+	})
+	if err == nil {
+		// A CSV export of no commits still has its header.
+		err = writer.Flush()
+	}
+	if err != nil {
+		return exported, errors.New("EXPORT_COMMITS_ERROR", "error exporting commits", err, errors.Critical)
+	}
+
+	logger.LogInfo(fmt.Sprintf("Exported %d commits of %s/%s as %s", exported, owner, name, format))
+	return exported, nil
+}
+
+// commitWriter writes commits in an export format, buffering them until flushed.
+type commitWriter interface {
+	Write(commits []domain.Commit) error
+	Flush() error
+}
+
+func newCommitWriter(format string, w io.Writer) commitWriter {
+	if format == domain.ExportFormatNDJSON {
+		buffered := bufio.NewWriter(w)
+		return &ndjsonCommitWriter{buffered: buffered, encoder: json.NewEncoder(buffered)}
+	}
+	writer := csv.NewWriter(w)
+	writer.Write(csvHeader) // Buffered until the first flush
+	return &csvCommitWriter{writer: writer}
+}
+
+// csvHeader names the columns of a CSV export. Parent SHAs are separated by spaces.
+var csvHeader = []string{
+	"hash", "commit_date", "message",
+	"author_name", "author_email", "author_date", "author_login",
+	"committer_name", "committer_email", "committer_login",
+	"parent_shas", "tree_sha", "verified",
+	"additions", "deletions", "total_changes",
+	"html_url", "unreachable",
+}
+
+type csvCommitWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvCommitWriter) Write(commits []domain.Commit) error {
+	for _, commit := range commits {
+		record := []string{
+			commit.Hash, commit.CommitDate.Format(time.RFC3339), commit.Message,
+			commit.AuthorName, commit.AuthorEmail, formatTime(commit.AuthorDate), commit.AuthorLogin,
+			commit.CommitterName, commit.CommitterEmail, commit.CommitterLogin,
+			strings.Join(commit.ParentSHAs, " "), commit.TreeSHA, strconv.FormatBool(commit.Verified),
+			formatInt(commit.Additions), formatInt(commit.Deletions), formatInt(commit.TotalChanges),
+			commit.HTMLURL, strconv.FormatBool(commit.Unreachable),
+		}
+		if err := c.writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvCommitWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonCommitWriter struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (n *ndjsonCommitWriter) Write(commits []domain.Commit) error {
+	for i := range commits {
+		if err := n.encoder.Encode(&commits[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *ndjsonCommitWriter) Flush() error {
+	return n.buffered.Flush()
+}
+
+// formatTime formats an optional time as RFC 3339, empty when it is unknown.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatInt formats an optional integer, empty when it is unknown.
+func formatInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
@@ -2,15 +2,24 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/olusolaa/github-monitor/internal/adapters/github"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/pkg/logger"
+	"path"
+	"sort"
+	"strings"
 )
 
 type GitHubService interface {
 	FetchRepository(ctx context.Context, owner, repoName string) (*domain.Repository, error)
-	FetchCommits(ctx context.Context, owner, repoName, since, until string, repoID int64, commitsChan chan<- []domain.Commit, errChan chan<- error)
+	FetchCommits(ctx context.Context, owner, repoName string, opts github.CommitListOptions, repoID int64, commitsChan chan<- []domain.Commit, errChan chan<- error)
+	ListRepositories(ctx context.Context, owner string, filter domain.RepositoryFilter) ([]domain.Repository, error)
+	ListBranches(ctx context.Context, owner, repoName string) ([]string, error)
+	CompareCommits(ctx context.Context, owner, repoName, base, head string) (*github.Comparison, error)
+	GetBranchHead(ctx context.Context, owner, repoName, branch string) (string, error)
+	FetchCommitChanges(ctx context.Context, owner, repoName, sha string) (*domain.CommitChanges, error)
 }
 
 type gitHubService struct {
@@ -23,12 +32,108 @@ func NewGitHubService(client *github.Client) GitHubService {
 
 func (s *gitHubService) FetchRepository(ctx context.Context, owner, repoName string) (*domain.Repository, error) {
 	apiRepo, err := s.client.GetRepository(ctx, owner, repoName)
+	if errors.Is(err, github.ErrNotModified) {
+		return nil, err
+	}
 	if err != nil {
 		logger.LogError(fmt.Errorf("failed to fetch repository info: %w", err))
 		return nil, err
 	}
 
-	repo := &domain.Repository{
+	repo := toDomainRepository(owner, apiRepo)
+	logger.LogInfo(fmt.Sprintf("Repository fetched: %s/%s", owner, repoName))
+	return repo, nil
+}
+
+// ListRepositories lists the repositories of a user or organization that pass filter.
+func (s *gitHubService) ListRepositories(ctx context.Context, owner string, filter domain.RepositoryFilter) ([]domain.Repository, error) {
+	apiRepos, err := s.client.ListRepositories(ctx, owner)
+	if err != nil {
+		logger.LogError(fmt.Errorf("failed to list repositories of %s: %w", owner, err))
+		return nil, err
+	}
+
+	var repos []domain.Repository
+	for i := range apiRepos {
+		if filter.Matches(apiRepos[i].Name, apiRepos[i].Language, apiRepos[i].Archived, apiRepos[i].Fork) {
+			repos = append(repos, *toDomainRepository(owner, &apiRepos[i]))
+		}
+	}
+	logger.LogInfo(fmt.Sprintf("Listed %d repositories of %s, %d match the filter", len(apiRepos), owner, len(repos)))
+	return repos, nil
+}
+
+// ListBranches lists the names of the branches of a repository.
+func (s *gitHubService) ListBranches(ctx context.Context, owner, repoName string) ([]string, error) {
+	apiBranches, err := s.client.ListBranches(ctx, owner, repoName)
+	if err != nil {
+		logger.LogError(fmt.Errorf("failed to list branches of %s/%s: %w", owner, repoName, err))
+		return nil, err
+	}
+
+	names := make([]string, len(apiBranches))
+	for i, branch := range apiBranches {
+		names[i] = branch.Name
+	}
+	return names, nil
+}
+
+// CompareCommits compares two commits or branches of a repository. github.ErrNotFound is
+// returned when either of them does not exist.
+func (s *gitHubService) CompareCommits(ctx context.Context, owner, repoName, base, head string) (*github.Comparison, error) {
+	comparison, err := s.client.CompareCommits(ctx, owner, repoName, base, head)
+	if err != nil && !errors.Is(err, github.ErrNotFound) {
+		logger.LogError(fmt.Errorf("failed to compare %s...%s in %s/%s: %w", base, head, owner, repoName, err))
+	}
+	return comparison, err
+}
+
+// GetBranchHead returns the SHA of the head commit of a branch. github.ErrNotFound is returned
+// when the branch does not exist.
+func (s *gitHubService) GetBranchHead(ctx context.Context, owner, repoName, branch string) (string, error) {
+	b, err := s.client.GetBranch(ctx, owner, repoName, branch)
+	if err != nil {
+		if !errors.Is(err, github.ErrNotFound) {
+			logger.LogError(fmt.Errorf("failed to get branch %s of %s/%s: %w", branch, owner, repoName, err))
+		}
+		return "", err
+	}
+	return b.Commit.Sha, nil
+}
+
+// FetchCommitChanges fetches the line statistics of a commit and the files it changed, along
+// with its metadata. github.ErrNotFound is returned when the commit does not exist.
+func (s *gitHubService) FetchCommitChanges(ctx context.Context, owner, repoName, sha string) (*domain.CommitChanges, error) {
+	commit, err := s.client.GetCommit(ctx, owner, repoName, sha)
+	if err != nil {
+		if !errors.Is(err, github.ErrNotFound) {
+			logger.LogError(fmt.Errorf("failed to fetch commit %s of %s/%s: %w", sha, owner, repoName, err))
+		}
+		return nil, err
+	}
+
+	changes := &domain.CommitChanges{
+		Additions:      commit.Stats.Additions,
+		Deletions:      commit.Stats.Deletions,
+		TotalChanges:   commit.Stats.Total,
+		Files:          make([]domain.CommitFile, len(commit.Files)),
+		FilesTruncated: commit.FilesTruncated,
+		Metadata:       s.convertToDomainCommits([]github.Commit{commit.Commit}, 0, "")[0],
+	}
+	for i, file := range commit.Files {
+		changes.Files[i] = domain.CommitFile{
+			Path:             file.Filename,
+			Status:           file.Status,
+			Additions:        file.Additions,
+			Deletions:        file.Deletions,
+			PreviousFilename: file.PreviousFilename,
+		}
+	}
+	return changes, nil
+}
+
+func toDomainRepository(owner string, apiRepo *github.Repository) *domain.Repository {
+	return &domain.Repository{
 		Owner:           owner,
 		Name:            apiRepo.Name,
 		Description:     apiRepo.Description,
@@ -40,23 +145,20 @@ func (s *gitHubService) FetchRepository(ctx context.Context, owner, repoName str
 		WatchersCount:   apiRepo.WatchersCount,
 		CreatedAt:       apiRepo.CreatedAt,
 		UpdatedAt:       apiRepo.UpdatedAt,
+		DefaultBranch:   apiRepo.DefaultBranch,
 	}
-	logger.LogInfo(fmt.Sprintf("Repository fetched: %s/%s", owner, repoName))
-	return repo, nil
 }
 
-func (s *gitHubService) FetchCommits(ctx context.Context, owner, repoName, since, until string, repoID int64, commitsChan chan<- []domain.Commit, errChan chan<- error) {
+func (s *gitHubService) FetchCommits(ctx context.Context, owner, repoName string, opts github.CommitListOptions, repoID int64, commitsChan chan<- []domain.Commit, errChan chan<- error) {
 	apiCommitsChan := make(chan []github.Commit)
-	apiErrChan := make(chan error)
+	apiErrChan := make(chan error, 1)
 
-	go s.client.GetCommits(ctx, owner, repoName, since, until, apiCommitsChan, apiErrChan)
+	go s.client.GetCommits(ctx, owner, repoName, opts, apiCommitsChan, apiErrChan)
 
 	var encounteredError error
 
+	// The client may still be sending when ctx is cancelled, so its channels are left open.
 	defer func() {
-		close(apiCommitsChan)
-		close(apiErrChan)
-
 		if encounteredError != nil {
 			errChan <- encounteredError
 		} else {
@@ -70,7 +172,7 @@ func (s *gitHubService) FetchCommits(ctx context.Context, owner, repoName, since
 			if !ok {
 				logger.LogWarning("API commits channel closed unexpectedly")
 			} else {
-				domainCommits := s.convertToDomainCommits(apiCommits, repoID)
+				domainCommits := s.convertToDomainCommits(apiCommits, repoID, opts.Branch)
 				select {
 				case commitsChan <- domainCommits:
 				case <-ctx.Done():
@@ -95,18 +197,104 @@ func (s *gitHubService) FetchCommits(ctx context.Context, owner, repoName, since
 	}
 }
 
-// convertToDomainCommits converts API commits to domain commits.
-func (s *gitHubService) convertToDomainCommits(apiCommits []github.Commit, repoID int64) []domain.Commit {
+// streamCommits runs FetchCommits and hands each page to handlePage until the listing ends,
+// handlePage fails or ctx is done. The fetch is cancelled when streamCommits returns.
+func streamCommits(ctx context.Context, gitHubService GitHubService, owner, name string, opts github.CommitListOptions, repoID int64, handlePage func([]domain.Commit) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	commitsChan := make(chan []domain.Commit)
+	errChan := make(chan error, 1)
+	go gitHubService.FetchCommits(ctx, owner, name, opts, repoID, commitsChan, errChan)
+
+	for {
+		select {
+		case commits := <-commitsChan:
+			if err := handlePage(commits); err != nil {
+				return err
+			}
+		case err := <-errChan:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// trackedBranches resolves the branches whose commits are collected for a repository, in name
+// order. Globs are matched against the branches of the repository, which are only listed when
+// there is one. Without configured branches only the default branch is tracked, which is the
+// empty name when it is not known yet.
+func trackedBranches(ctx context.Context, gitHubService GitHubService, repository *domain.Repository) ([]string, error) {
+	if len(repository.Branches) == 0 {
+		return []string{repository.DefaultBranch}, nil
+	}
+
+	var existing []string
+	listed := false
+	seen := make(map[string]bool)
+	for _, pattern := range repository.Branches {
+		if !strings.ContainsAny(pattern, `*?[\`) {
+			seen[pattern] = true
+			continue
+		}
+		if !listed {
+			var err error
+			if existing, err = gitHubService.ListBranches(ctx, repository.Owner, repository.Name); err != nil {
+				return nil, err
+			}
+			listed = true
+		}
+		for _, branch := range existing {
+			if matched, _ := path.Match(pattern, branch); matched {
+				seen[branch] = true
+			}
+		}
+	}
+
+	branches := make([]string, 0, len(seen))
+	for branch := range seen {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+	return branches, nil
+}
+
+// convertToDomainCommits converts API commits to domain commits listed from branch.
+func (s *gitHubService) convertToDomainCommits(apiCommits []github.Commit, repoID int64, branch string) []domain.Commit {
 	domainCommits := make([]domain.Commit, len(apiCommits))
 	for i, commit := range apiCommits {
+		author, committer := commit.Commit.Author, commit.Commit.Committer
+		parents := make([]string, len(commit.Parents))
+		for j, parent := range commit.Parents {
+			parents[j] = parent.Sha
+		}
+
 		domainCommits[i] = domain.Commit{
-			RepositoryID: repoID,
-			Hash:         commit.Sha,
-			Message:      commit.Commit.Message,
-			AuthorName:   commit.Commit.Committer.Name,
-			AuthorEmail:  commit.Commit.Committer.Email,
-			CommitDate:   commit.Commit.Committer.Date,
-			URL:          commit.Commit.Url,
+			RepositoryID:       repoID,
+			Hash:               commit.Sha,
+			Message:            commit.Commit.Message,
+			AuthorName:         author.Name,
+			AuthorEmail:        author.Email,
+			AuthorDate:         &author.Date,
+			CommitterName:      committer.Name,
+			CommitterEmail:     committer.Email,
+			CommitDate:         committer.Date,
+			URL:                commit.Commit.Url,
+			ParentSHAs:         parents,
+			TreeSHA:            commit.Commit.Tree.Sha,
+			Verified:           commit.Commit.Verification.Verified,
+			VerificationReason: commit.Commit.Verification.Reason,
+			HTMLURL:            commit.HtmlUrl,
+			Branch:             branch,
+		}
+		if commit.Author != nil {
+			domainCommits[i].AuthorLogin = commit.Author.Login
+			domainCommits[i].AuthorID = &commit.Author.ID
+		}
+		if commit.Committer != nil {
+			domainCommits[i].CommitterLogin = commit.Committer.Login
+			domainCommits[i].CommitterID = &commit.Committer.ID
 		}
 	}
 	return domainCommits
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/pkg/errors"
 	"time"
@@ -11,6 +13,9 @@ import (
 	"github.com/olusolaa/github-monitor/pkg/utils"
 )
 
+// ErrRepositoryPaused is returned by PollRepository for repositories whose polling is paused.
+var ErrRepositoryPaused = stderrors.New("repository monitoring is paused")
+
 type MonitorService struct {
 	repositoryService   RepositoryService
 	commitService       CommitService
@@ -30,7 +35,17 @@ func NewMonitorService(repositoryService RepositoryService, commitService Commit
 }
 
 // MonitorRepository oversees monitoring both repository and commit information for changes.
+// Paused repositories are skipped.
 func (m *MonitorService) MonitorRepository(ctx context.Context, repositoryID int64) error {
+	paused, err := m.isPaused(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if paused {
+		logger.LogDebug(fmt.Sprintf("Monitoring of repository ID %d is paused, skipping", repositoryID))
+		return nil
+	}
+
 	retryCount := 0
 	for {
 		err := m.syncRepositoryAndCommits(ctx, repositoryID)
@@ -45,11 +60,62 @@ func (m *MonitorService) MonitorRepository(ctx context.Context, repositoryID int
 		}
 
 		backoffDuration := utils.ExponentialBackoff(retryCount, m.initialRetryBackoff)
-		time.Sleep(backoffDuration)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoffDuration):
+		}
 	}
 	return nil
 }
 
+// PollRepository monitors a repository like MonitorRepository and returns the number of its
+// stored commits made during the last window, its recent commit rate. Paused repositories are
+// not polled and yield ErrRepositoryPaused.
+func (m *MonitorService) PollRepository(ctx context.Context, repositoryID int64, window time.Duration) (int, error) {
+	paused, err := m.isPaused(ctx, repositoryID)
+	if err != nil {
+		return 0, err
+	}
+	if paused {
+		return 0, ErrRepositoryPaused
+	}
+
+	if err := m.MonitorRepository(ctx, repositoryID); err != nil {
+		return 0, err
+	}
+
+	count, err := m.commitService.CountRecentCommits(ctx, repositoryID, time.Now().Add(-window))
+	if err != nil {
+		return 0, fmt.Errorf("could not count recent commits: %w", err)
+	}
+	return count, nil
+}
+
+// SavePollState records the polling interval computed for a repository by adaptive polling.
+func (m *MonitorService) SavePollState(ctx context.Context, repositoryID int64, interval time.Duration, emptyPolls int) error {
+	return m.repositoryService.UpdatePollState(ctx, repositoryID, interval, emptyPolls)
+}
+
+// isPaused reports whether polling of a repository has been paused.
+func (m *MonitorService) isPaused(ctx context.Context, repositoryID int64) (bool, error) {
+	repository, err := m.GetRepository(ctx, repositoryID)
+	if err != nil {
+		return false, err
+	}
+	return repository != nil && repository.IsPaused(), nil
+}
+
+// GetRepository retrieves a repository by its ID, nil when it does not exist.
+func (m *MonitorService) GetRepository(ctx context.Context, repositoryID int64) (*domain.Repository, error) {
+	return getRepositoryByID(ctx, m.repositoryService, repositoryID)
+}
+
+// StopRepository cancels the unfinished backfill of a repository that is no longer monitored.
+func (m *MonitorService) StopRepository(ctx context.Context, repositoryID int64) error {
+	return m.commitService.CancelBackfill(ctx, repositoryID)
+}
+
 // syncRepositoryAndCommits fetches and updates both repository information and commits.
 func (m *MonitorService) syncRepositoryAndCommits(ctx context.Context, repositoryID int64) error {
 	if err := m.SyncRepositoryInfo(ctx, repositoryID); err != nil {
@@ -59,22 +125,108 @@ func (m *MonitorService) syncRepositoryAndCommits(ctx context.Context, repositor
 	return m.MonitorRepositoryCommits(ctx, repositoryID)
 }
 
+// MonitorRepositoryCommits fetches the commits of every tracked branch newer than the latest one
+// stored for that branch, or for the repository when the branch has none yet. A branch whose
+// history was rewritten is collected again from where it diverged first. The first page is requested
+// conditionally, so an unchanged repository costs no decoding or writes.
 func (m *MonitorService) MonitorRepositoryCommits(ctx context.Context, repositoryID int64) error {
 	latestCommit, err := m.commitService.GetLatestCommit(ctx, repositoryID)
 	if err != nil {
 		return fmt.Errorf("could not get latest commit: %w", err)
 	}
 
-	var since string
-	if latestCommit != nil {
-		since = latestCommit.CommitDate.Format(time.RFC3339)
+	repository, err := m.GetRepository(ctx, repositoryID)
+	if err != nil {
+		return fmt.Errorf("could not get repository: %w", err)
+	}
+	if repository == nil {
+		return errors.New("REPOSITORY_NOT_FOUND", "repository not found", fmt.Errorf("repository ID %d not found", repositoryID), errors.Warning)
+	}
+
+	branches, err := trackedBranches(ctx, m.gitHubService, repository)
+	if err != nil {
+		return fmt.Errorf("could not resolve tracked branches: %w", err)
 	}
 
-	owner, name, err := m.repositoryService.GetOwnerAndRepoName(ctx, repositoryID)
+	for _, branch := range branches {
+		var from *time.Time
+		if latestCommit != nil {
+			from = &latestCommit.CommitDate
+		}
+		if branch != "" {
+			latestOnBranch, err := m.commitService.GetLatestBranchCommit(ctx, repositoryID, branch)
+			if err != nil {
+				return fmt.Errorf("could not get latest commit of branch %s: %w", branch, err)
+			}
+			if latestOnBranch != nil {
+				from = &latestOnBranch.CommitDate
+				if err := m.verifyBranchHistory(ctx, repository, branch, latestOnBranch); err != nil {
+					return fmt.Errorf("could not verify history of branch %s: %w", branch, err)
+				}
+			}
+		}
+
+		var since string
+		if from != nil {
+			since = from.Format(time.RFC3339)
+		}
+		opts := github.CommitListOptions{Since: since, Branch: branch}
+		if err := m.monitorBranchCommits(github.WithConditionalRequests(ctx), repository, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyBranchHistory checks that latest, the latest commit stored for a branch, is still
+// reachable from the head of the branch. When the history was rewritten, such as by a
+// force-push, the branch is collected again from its merge base with its former history, the
+// rewrite is recorded and the commits no longer on any branch are marked unreachable.
+func (m *MonitorService) verifyBranchHistory(ctx context.Context, repository *domain.Repository, branch string, latest *domain.Commit) error {
+	head, err := m.gitHubService.GetBranchHead(ctx, repository.Owner, repository.Name, branch)
+	if stderrors.Is(err, github.ErrNotFound) {
+		logger.LogWarning(fmt.Sprintf("Branch %s of %s/%s no longer exists", branch, repository.Owner, repository.Name))
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("could not get repository owner and name: %w", err)
+		return err
+	}
+	if head == latest.Hash {
+		return nil
+	}
+
+	comparison, compareErr := m.gitHubService.CompareCommits(ctx, repository.Owner, repository.Name, latest.Hash, head)
+	if compareErr != nil && !stderrors.Is(compareErr, github.ErrNotFound) {
+		return compareErr
+	}
+	if compareErr == nil && comparison.HeadContainsBase() {
+		return nil
 	}
 
+	// Without a merge base, GitHub no longer knows the stored commit and the branch is collected
+	// again from the start date of the repository.
+	rewrite := &domain.HistoryRewrite{
+		RepositoryID: repository.ID,
+		Branch:       branch,
+		BeforeSHA:    latest.Hash,
+		AfterSHA:     head,
+	}
+	var since time.Time
+	if compareErr == nil && comparison.MergeBaseCommit.Sha != "" {
+		rewrite.MergeBaseSHA = comparison.MergeBaseCommit.Sha
+		since = comparison.MergeBaseCommit.Commit.Committer.Date
+	}
+
+	if _, err := m.commitService.RecordHistoryRewrite(ctx, rewrite, since); err != nil {
+		return err
+	}
+	logger.LogWarning(fmt.Sprintf("History of branch %s of %s/%s was rewritten from %s to %s, %d commits orphaned",
+		branch, repository.Owner, repository.Name, rewrite.BeforeSHA, rewrite.AfterSHA, rewrite.OrphanedCommits))
+	return nil
+}
+
+// monitorBranchCommits fetches and saves the commits of a repository selected by opts.
+func (m *MonitorService) monitorBranchCommits(ctx context.Context, repository *domain.Repository, opts github.CommitListOptions) error {
 	domainCommitsChan := make(chan []domain.Commit)
 	errChan := make(chan error)
 
@@ -83,7 +235,7 @@ func (m *MonitorService) MonitorRepositoryCommits(ctx context.Context, repositor
 		close(errChan)
 	}()
 
-	go m.gitHubService.FetchCommits(ctx, owner, name, since, "", repositoryID, domainCommitsChan, errChan)
+	go m.gitHubService.FetchCommits(ctx, repository.Owner, repository.Name, opts, repository.ID, domainCommitsChan, errChan)
 
 	var encounteredError error
 
@@ -113,13 +265,18 @@ func (m *MonitorService) MonitorRepositoryCommits(ctx context.Context, repositor
 }
 
 // SyncRepositoryInfo fetches and updates repository information.
+// Nothing is written when GitHub reports the repository as unchanged.
 func (m *MonitorService) SyncRepositoryInfo(ctx context.Context, repositoryID int64) error {
 	owner, name, err := m.repositoryService.GetOwnerAndRepoName(ctx, repositoryID)
 	if err != nil {
 		return err
 	}
 
-	updatedRepository, err := m.gitHubService.FetchRepository(ctx, owner, name)
+	updatedRepository, err := m.gitHubService.FetchRepository(github.WithConditionalRequests(ctx), owner, name)
+	if stderrors.Is(err, github.ErrNotModified) {
+		logger.LogDebug(fmt.Sprintf("Repository %s/%s not modified, skipping update", owner, name))
+		return nil
+	}
 	if err != nil {
 		return err
 	}
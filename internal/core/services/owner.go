@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+type OwnerService interface {
+	MonitorOwner(ctx context.Context, owner string, filter domain.RepositoryFilter) ([]string, error)
+	Reconcile(ctx context.Context, declared []string) error
+	DiscoverRepositories(ctx context.Context)
+	DiscoveryManager(interval time.Duration)
+}
+
+type ownerService struct {
+	gitHubService     GitHubService
+	repositoryService RepositoryService
+	ownerRepo         postgresdb.OwnerRepository
+}
+
+// NewOwnerService creates a service monitoring every repository of users and organizations.
+func NewOwnerService(gitHubService GitHubService, repositoryService RepositoryService, ownerRepo postgresdb.OwnerRepository) OwnerService {
+	return &ownerService{
+		gitHubService:     gitHubService,
+		repositoryService: repositoryService,
+		ownerRepo:         ownerRepo,
+	}
+}
+
+// MonitorOwner monitors every repository of a user or organization that passes filter and
+// records the owner, so that repositories created later are discovered as well. It returns the
+// names of the repositories that were not monitored yet, which are added in the background.
+func (s *ownerService) MonitorOwner(ctx context.Context, owner string, filter domain.RepositoryFilter) ([]string, error) {
+	return s.monitorOwner(ctx, &domain.MonitoredOwner{Owner: owner, RepositoryFilter: filter})
+}
+
+// Reconcile monitors every repository of the owners declared in the repositories file, and stops
+// monitoring the owners removed from it together with their repositories, except those declared
+// in the file themselves. Owners are matched case-insensitively, as GitHub logins are. The file
+// declares no filter, so an owner already monitored keeps its filter.
+func (s *ownerService) Reconcile(ctx context.Context, declared []string) error {
+	owners, err := s.ownerRepo.FindAll(ctx)
+	if err != nil {
+		logger.LogError(err)
+		return err
+	}
+	existing := make(map[string]domain.MonitoredOwner, len(owners))
+	for _, owner := range owners {
+		existing[strings.ToLower(owner.Owner)] = owner
+	}
+
+	keep := make(map[string]bool)
+	for _, owner := range declared {
+		key := strings.ToLower(owner)
+		// A declared owner that cannot be reconciled is kept as it is rather than stopped.
+		keep[key] = true
+		monitored := &domain.MonitoredOwner{Owner: owner, Managed: true}
+		if current, ok := existing[key]; ok {
+			monitored.Owner, monitored.RepositoryFilter = current.Owner, current.RepositoryFilter
+		}
+		if _, err := s.monitorOwner(ctx, monitored); err != nil {
+			logger.LogError(fmt.Errorf("failed to reconcile repositories of %s: %w", owner, err))
+		}
+	}
+
+	for _, owner := range owners {
+		if !owner.Managed || keep[strings.ToLower(owner.Owner)] {
+			continue
+		}
+
+		if err := s.ownerRepo.Delete(ctx, owner.Owner); err != nil {
+			logger.LogError(err)
+			return err
+		}
+		stopped, err := s.repositoryService.StopOwnerRepositories(ctx, owner.Owner)
+		if err != nil {
+			return err
+		}
+		logger.LogInfo(fmt.Sprintf("Stopped monitoring %d repositories of %s removed from the repositories file", stopped, owner.Owner))
+	}
+	return nil
+}
+
+// monitorOwner records a monitored owner and adds its repositories that pass its filter.
+func (s *ownerService) monitorOwner(ctx context.Context, monitored *domain.MonitoredOwner) ([]string, error) {
+	owner, filter := monitored.Owner, monitored.RepositoryFilter
+	if err := filter.Validate(); err != nil {
+		return nil, errors.New("INVALID_FILTER", "invalid repository filter", err, errors.Warning)
+	}
+
+	names, err := s.newRepositories(ctx, owner, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ownerRepo.Upsert(ctx, monitored); err != nil {
+		logger.LogError(err)
+		return nil, errors.New("SAVE_OWNER_ERROR", "error saving monitored owner", err, errors.Critical)
+	}
+
+	s.addRepositories(owner, names)
+	logger.LogInfo(fmt.Sprintf("Monitoring repositories of %s, %d new", owner, len(names)))
+	return names, nil
+}
+
+// DiscoverRepositories adds the repositories created since the monitored owners were last
+// looked at.
+func (s *ownerService) DiscoverRepositories(ctx context.Context) {
+	owners, err := s.ownerRepo.FindAll(ctx)
+	if err != nil {
+		logger.LogError(errors.New("FIND_OWNERS_ERROR", "error finding monitored owners", err, errors.Critical))
+		return
+	}
+
+	for _, owner := range owners {
+		names, err := s.newRepositories(ctx, owner.Owner, owner.RepositoryFilter)
+		if err != nil {
+			logger.LogError(err)
+			continue
+		}
+		if len(names) > 0 {
+			logger.LogInfo(fmt.Sprintf("Discovered %d new repositories of %s", len(names), owner.Owner))
+		}
+		s.addRepositories(owner.Owner, names)
+	}
+}
+
+// DiscoveryManager discovers new repositories of the monitored owners every interval.
+// A non-positive interval disables discovery.
+func (s *ownerService) DiscoveryManager(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.DiscoverRepositories(github.WithRateLimitWait(context.Background()))
+	}
+}
+
+// newRepositories lists the repositories of an owner that pass filter and are not known yet.
+// Repositories that are known, including stopped ones, are left alone.
+func (s *ownerService) newRepositories(ctx context.Context, owner string, filter domain.RepositoryFilter) ([]string, error) {
+	repos, err := s.gitHubService.ListRepositories(ctx, owner, filter)
+	if err != nil {
+		return nil, errors.New("LIST_REPOSITORIES_ERROR", fmt.Sprintf("error listing repositories of %s", owner), err, errors.Critical)
+	}
+
+	var names []string
+	for _, repo := range repos {
+		known, err := s.repositoryService.GetRepository(ctx, repo.Name, owner)
+		if err != nil {
+			return nil, errors.New("GET_REPOSITORY_ERROR", "error getting repository", err, errors.Critical)
+		}
+		if known == nil {
+			names = append(names, repo.Name)
+		}
+	}
+	return names, nil
+}
+
+// addRepositories queues repositories for monitoring without waiting for the queue to drain.
+func (s *ownerService) addRepositories(owner string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	go func() {
+		for _, name := range names {
+			if err := s.repositoryService.AddRepository(owner, name); err != nil {
+				logger.LogError(err)
+			}
+		}
+	}()
+}
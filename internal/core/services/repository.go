@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/errors"
 	"github.com/olusolaa/github-monitor/pkg/logger"
+	"github.com/olusolaa/github-monitor/pkg/utils"
+	"strings"
+	"time"
 )
 
 type RepositoryService interface {
@@ -13,7 +17,17 @@ type RepositoryService interface {
 	GetOwnerAndRepoName(ctx context.Context, repoID int64) (string, string, error)
 	UpsertRepository(ctx context.Context, repository *domain.Repository) error
 	AddRepository(owner, repo string) error
-	FetchRepository(ctx context.Context, owner, repo string, commitChan chan int64) error
+	RemoveRepository(ctx context.Context, owner, repo string, purge bool) error
+	PauseRepository(ctx context.Context, owner, repo string) error
+	ResumeRepository(ctx context.Context, owner, repo string) error
+	SetSchedule(ctx context.Context, owner, repo, schedule string) error
+	SetBranches(ctx context.Context, owner, repo string, branches []string) error
+	RenameRepository(ctx context.Context, owner, oldName, newName string) (*domain.Repository, error)
+	UpdatePollState(ctx context.Context, repoID int64, interval time.Duration, emptyPolls int) error
+	FetchRepository(ctx context.Context, owner, repo string, commitChan chan int64) (*domain.Repository, error)
+	RestoreMonitoring(ctx context.Context, commitChan chan int64) error
+	Reconcile(ctx context.Context, declared []domain.Repository) error
+	StopOwnerRepositories(ctx context.Context, owner string) (int, error)
 	RepositoryManager(commitChan chan int64)
 }
 
@@ -24,16 +38,23 @@ type RepoRequest struct {
 }
 
 type repositoryService struct {
-	ghService GitHubService
-	repoRepo  postgresdb.RepositoryRepository
-	repoChan  chan RepoRequest
+	ghService      GitHubService
+	repoRepo       postgresdb.RepositoryRepository
+	repoChan       chan RepoRequest
+	removalChan    chan int64
+	rescheduleChan chan int64
 }
 
-func NewRepositoryService(ghService GitHubService, repoRepo postgresdb.RepositoryRepository, repoChan chan RepoRequest, commitChan chan int64) RepositoryService {
+// NewRepositoryService creates a repository service. Repositories that stop being monitored
+// are published on removalChan so that their polling and backfills can be stopped, and those
+// whose schedule changes on rescheduleChan.
+func NewRepositoryService(ghService GitHubService, repoRepo postgresdb.RepositoryRepository, repoChan chan RepoRequest, commitChan, removalChan, rescheduleChan chan int64) RepositoryService {
 	s := &repositoryService{
-		ghService: ghService,
-		repoRepo:  repoRepo,
-		repoChan:  repoChan,
+		ghService:      ghService,
+		repoRepo:       repoRepo,
+		repoChan:       repoChan,
+		removalChan:    removalChan,
+		rescheduleChan: rescheduleChan,
 	}
 	go s.RepositoryManager(commitChan) // Start the manager goroutine with the commitChan
 	return s
@@ -49,12 +70,165 @@ func (s *repositoryService) AddRepository(owner, repo string) error {
 	return nil
 }
 
+// RemoveRepository stops monitoring a repository. With purge its commits, backfill jobs and
+// the repository itself are deleted; otherwise they are kept and the repository is marked stopped.
+func (s *repositoryService) RemoveRepository(ctx context.Context, owner, repo string, purge bool) error {
+	repository, err := s.repoRepo.FindByNameAndOwner(ctx, repo, owner)
+	if err != nil {
+		logger.LogError(err)
+		return err
+	}
+	if repository == nil {
+		return errors.New("REPOSITORY_NOT_FOUND", "repository is not monitored", fmt.Errorf("%s/%s not found", owner, repo), errors.Warning)
+	}
+
+	if purge {
+		err = s.repoRepo.Delete(ctx, repository.ID)
+	} else {
+		err = s.repoRepo.UpdateMonitoringStatus(ctx, repository.ID, domain.RepositoryStatusStopped)
+	}
+	if err != nil {
+		logger.LogError(err)
+		return err
+	}
+
+	if s.removalChan != nil {
+		s.removalChan <- repository.ID
+	}
+	logger.LogInfo(fmt.Sprintf("Stopped monitoring repository: %s/%s (purged: %t)", owner, repo, purge))
+	return nil
+}
+
+// PauseRepository stops polling a repository until it is resumed. Its history is kept.
+func (s *repositoryService) PauseRepository(ctx context.Context, owner, repo string) error {
+	repository, err := s.findMonitored(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if err := s.setMonitoringStatus(ctx, repository, domain.RepositoryStatusPaused); err != nil {
+		return err
+	}
+	logger.LogInfo(fmt.Sprintf("Paused monitoring of repository: %s/%s", owner, repo))
+	return nil
+}
+
+// ResumeRepository resumes polling a paused repository.
+func (s *repositoryService) ResumeRepository(ctx context.Context, owner, repo string) error {
+	repository, err := s.findMonitored(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if err := s.setMonitoringStatus(ctx, repository, domain.RepositoryStatusActive); err != nil {
+		return err
+	}
+	logger.LogInfo(fmt.Sprintf("Resumed monitoring of repository: %s/%s", owner, repo))
+	return nil
+}
+
+// SetSchedule sets the polling schedule of a repository, either a duration such as "5m" or a
+// cron expression. An empty schedule falls back to POLL_INTERVAL.
+func (s *repositoryService) SetSchedule(ctx context.Context, owner, repo, schedule string) error {
+	if schedule != "" {
+		if _, _, err := utils.ParseSchedule(schedule); err != nil {
+			return errors.New("INVALID_SCHEDULE", "invalid schedule", err, errors.Warning)
+		}
+	}
+
+	repository, err := s.findMonitored(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if err := s.repoRepo.UpdateSchedule(ctx, repository.ID, schedule); err != nil {
+		logger.LogError(err)
+		return err
+	}
+
+	if s.rescheduleChan != nil {
+		s.rescheduleChan <- repository.ID
+	}
+	logger.LogInfo(fmt.Sprintf("Schedule of repository %s/%s set to %q", owner, repo, schedule))
+	return nil
+}
+
+// SetBranches sets the branches whose commits are collected for a repository, as names or globs
+// such as "release/*". No branches fall back to the default branch. Newly tracked branches are
+// followed from the latest stored commit on the next poll.
+func (s *repositoryService) SetBranches(ctx context.Context, owner, repo string, branches []string) error {
+	if err := domain.ValidateBranchPatterns(branches); err != nil {
+		return errors.New("INVALID_BRANCHES", "invalid branches", err, errors.Warning)
+	}
+
+	repository, err := s.findMonitored(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if err := s.repoRepo.UpdateBranches(ctx, repository.ID, branches); err != nil {
+		logger.LogError(err)
+		return err
+	}
+
+	logger.LogInfo(fmt.Sprintf("Tracked branches of repository %s/%s set to %q", owner, repo, branches))
+	return nil
+}
+
+// RenameRepository follows the rename of a repository on GitHub, keeping its commits and
+// monitoring state. It returns the renamed repository, or nil when oldName is not stored.
+func (s *repositoryService) RenameRepository(ctx context.Context, owner, oldName, newName string) (*domain.Repository, error) {
+	repository, err := s.repoRepo.FindByNameAndOwner(ctx, oldName, owner)
+	if err != nil {
+		logger.LogError(err)
+		return nil, err
+	}
+	if repository == nil {
+		return nil, nil
+	}
+	if err := s.repoRepo.UpdateName(ctx, repository.ID, newName); err != nil {
+		logger.LogError(err)
+		return nil, err
+	}
+	repository.Name = newName
+
+	logger.LogInfo(fmt.Sprintf("Repository %s/%s renamed to %s/%s", owner, oldName, owner, newName))
+	return repository, nil
+}
+
+// UpdatePollState records the polling interval computed for a repository by adaptive polling.
+func (s *repositoryService) UpdatePollState(ctx context.Context, repoID int64, interval time.Duration, emptyPolls int) error {
+	if err := s.repoRepo.UpdatePollState(ctx, repoID, int(interval/time.Second), emptyPolls); err != nil {
+		logger.LogError(err)
+		return err
+	}
+	return nil
+}
+
+// findMonitored retrieves a repository that has not been stopped.
+func (s *repositoryService) findMonitored(ctx context.Context, owner, repo string) (*domain.Repository, error) {
+	repository, err := s.repoRepo.FindByNameAndOwner(ctx, repo, owner)
+	if err != nil {
+		logger.LogError(err)
+		return nil, err
+	}
+	if repository == nil || !repository.IsMonitored() {
+		return nil, errors.New("REPOSITORY_NOT_FOUND", "repository is not monitored", fmt.Errorf("%s/%s not found", owner, repo), errors.Warning)
+	}
+	return repository, nil
+}
+
+func (s *repositoryService) setMonitoringStatus(ctx context.Context, repository *domain.Repository, status string) error {
+	if err := s.repoRepo.UpdateMonitoringStatus(ctx, repository.ID, status); err != nil {
+		logger.LogError(err)
+		return err
+	}
+	repository.MonitoringStatus = status
+	return nil
+}
+
 func (s *repositoryService) RepositoryManager(commitChan chan int64) {
 	for {
 		select {
 		case repoRequest := <-s.repoChan:
 			ctx := context.Background()
-			err := s.FetchRepository(ctx, repoRequest.Owner, repoRequest.Name, commitChan)
+			_, err := s.FetchRepository(ctx, repoRequest.Owner, repoRequest.Name, commitChan)
 			if err != nil {
 				repoRequest.retry++
 				if repoRequest.retry < 3 {
@@ -67,26 +241,160 @@ func (s *repositoryService) RepositoryManager(commitChan chan int64) {
 	}
 }
 
-func (s *repositoryService) FetchRepository(ctx context.Context, owner, repo string, commitChan chan int64) error {
+// FetchRepository fetches a repository from GitHub, stores it and publishes it on commitChan
+// when given. It returns the stored repository, under the owner and name GitHub reports.
+func (s *repositoryService) FetchRepository(ctx context.Context, owner, repo string, commitChan chan int64) (*domain.Repository, error) {
 	repository, err := s.ghService.FetchRepository(ctx, owner, repo)
 	if err != nil {
 		logger.LogError(err)
-		return err
+		return nil, err
 	}
 
 	err = s.UpsertRepository(ctx, repository)
 	if err != nil {
 		logger.LogError(err)
-		return err
+		return nil, err
+	}
+
+	// Adding a stopped repository again resumes tracking it; a paused one stays paused.
+	if !repository.IsMonitored() {
+		if err := s.setMonitoringStatus(ctx, repository, domain.RepositoryStatusActive); err != nil {
+			return nil, err
+		}
 	}
 
 	if commitChan != nil {
 		commitChan <- repository.ID
 	}
 	logger.LogInfo(fmt.Sprintf("Initialized repository and published event for fetching commits for repo: %s/%s", owner, repo))
+	return repository, nil
+}
+
+// RestoreMonitoring publishes every repository that has not been stopped on commitChan, so
+// that monitoring carries on where it left off before a restart.
+func (s *repositoryService) RestoreMonitoring(ctx context.Context, commitChan chan int64) error {
+	repositories, err := s.repoRepo.FindMonitored(ctx)
+	if err != nil {
+		logger.LogError(err)
+		return err
+	}
+
+	for _, repository := range repositories {
+		commitChan <- repository.ID
+	}
+	logger.LogInfo(fmt.Sprintf("Restored monitoring of %d repositories", len(repositories)))
 	return nil
 }
 
+// Reconcile brings the managed repositories in line with those declared in the repositories
+// file. Declared repositories are added, take the declared settings and are monitored again if
+// they were stopped; managed repositories that are no longer declared stop being monitored.
+// It runs at startup, before monitoring is scheduled.
+func (s *repositoryService) Reconcile(ctx context.Context, declared []domain.Repository) error {
+	keep := make(map[string]bool)
+	for i := range declared {
+		// A declared repository that cannot be reconciled is kept as it is rather than stopped.
+		keep[repositoryKey(declared[i].Owner, declared[i].Name)] = true
+		if err := s.reconcileRepository(ctx, &declared[i]); err != nil {
+			logger.LogError(fmt.Errorf("failed to reconcile repository %s/%s: %w", declared[i].Owner, declared[i].Name, err))
+		}
+	}
+
+	managed, err := s.repoRepo.FindManaged(ctx)
+	if err != nil {
+		logger.LogError(err)
+		return err
+	}
+	for i := range managed {
+		repository := &managed[i]
+		if keep[repositoryKey(repository.Owner, repository.Name)] {
+			continue
+		}
+
+		repository.Managed = false
+		if err := s.repoRepo.UpdateSettings(ctx, repository); err != nil {
+			logger.LogError(err)
+			return err
+		}
+		if repository.IsMonitored() {
+			if err := s.setMonitoringStatus(ctx, repository, domain.RepositoryStatusStopped); err != nil {
+				return err
+			}
+		}
+		logger.LogInfo(fmt.Sprintf("Stopped monitoring repository %s/%s removed from the repositories file", repository.Owner, repository.Name))
+	}
+	return nil
+}
+
+// StopOwnerRepositories stops monitoring the repositories of an owner that are not declared in
+// the repositories file, keeping their history, and returns how many there were. Like Reconcile,
+// it runs before monitoring starts, so no polling has to be stopped.
+func (s *repositoryService) StopOwnerRepositories(ctx context.Context, owner string) (int, error) {
+	repositories, err := s.repoRepo.FindMonitored(ctx)
+	if err != nil {
+		logger.LogError(err)
+		return 0, err
+	}
+
+	stopped := 0
+	for i := range repositories {
+		repository := &repositories[i]
+		if repository.Owner != owner || repository.Managed {
+			continue
+		}
+		if err := s.setMonitoringStatus(ctx, repository, domain.RepositoryStatusStopped); err != nil {
+			return stopped, err
+		}
+		stopped++
+	}
+	return stopped, nil
+}
+
+// reconcileRepository adds a declared repository if it is not known yet and applies its settings.
+func (s *repositoryService) reconcileRepository(ctx context.Context, declared *domain.Repository) error {
+	repository, err := s.repoRepo.FindByNameAndOwner(ctx, declared.Name, declared.Owner)
+	if err != nil {
+		return err
+	}
+	if repository == nil {
+		if repository, err = s.ghService.FetchRepository(ctx, declared.Owner, declared.Name); err != nil {
+			return err
+		}
+		if err := s.UpsertRepository(ctx, repository); err != nil {
+			return err
+		}
+		logger.LogInfo(fmt.Sprintf("Added repository %s/%s from the repositories file", declared.Owner, declared.Name))
+	}
+
+	if !repository.IsMonitored() {
+		if err := s.setMonitoringStatus(ctx, repository, domain.RepositoryStatusActive); err != nil {
+			return err
+		}
+	}
+
+	repository.StartDate = declared.StartDate
+	repository.EndDate = declared.EndDate
+	repository.Branches = declared.Branches
+	repository.Schedule = declared.Schedule
+	repository.Labels = declared.Labels
+	repository.Managed = true
+	return s.repoRepo.UpdateSettings(ctx, repository)
+}
+
+// repositoryKey identifies a repository by owner and name, which GitHub treats case-insensitively.
+func repositoryKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
+
+// getRepositoryByID retrieves a repository by its ID, nil when it does not exist.
+func getRepositoryByID(ctx context.Context, repositoryService RepositoryService, repositoryID int64) (*domain.Repository, error) {
+	owner, name, err := repositoryService.GetOwnerAndRepoName(ctx, repositoryID)
+	if err != nil || owner == "" {
+		return nil, err
+	}
+	return repositoryService.GetRepository(ctx, name, owner)
+}
+
 // GetRepository fetches repository information either from the database or GitHub API.
 func (s *repositoryService) GetRepository(ctx context.Context, repoName, owner string) (*domain.Repository, error) {
 	repository, err := s.repoRepo.FindByNameAndOwner(ctx, repoName, owner)
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+	"github.com/olusolaa/github-monitor/pkg/logger"
+)
+
+// branchRefPrefix prefixes the name of a branch in the ref of a push event.
+const branchRefPrefix = "refs/heads/"
+
+type WebhookService interface {
+	HandleDelivery(ctx context.Context, deliveryID, event string, payload []byte) (bool, error)
+}
+
+type webhookService struct {
+	repositoryService RepositoryService
+	commitService     CommitService
+	deliveryRepo      postgresdb.WebhookDeliveryRepository
+}
+
+func NewWebhookService(repositoryService RepositoryService, commitService CommitService, deliveryRepo postgresdb.WebhookDeliveryRepository) WebhookService {
+	return &webhookService{
+		repositoryService: repositoryService,
+		commitService:     commitService,
+		deliveryRepo:      deliveryRepo,
+	}
+}
+
+// HandleDelivery records and processes a webhook delivery. It returns false without doing
+// anything when the delivery ID has already been processed.
+func (s *webhookService) HandleDelivery(ctx context.Context, deliveryID, event string, payload []byte) (bool, error) {
+	delivery := &domain.WebhookDelivery{
+		DeliveryID: deliveryID,
+		Event:      event,
+		Payload:    string(payload),
+	}
+	claimed, err := s.deliveryRepo.Claim(ctx, delivery)
+	if err != nil {
+		logger.LogError(errors.New("CLAIM_DELIVERY_ERROR", "error recording webhook delivery", err, errors.Critical))
+		return false, err
+	}
+	if !claimed {
+		logger.LogInfo(fmt.Sprintf("Skipping duplicate webhook delivery %s", deliveryID))
+		return false, nil
+	}
+
+	status, processErr := s.process(ctx, event, payload)
+	errMsg := ""
+	if processErr != nil {
+		status = domain.WebhookStatusFailed
+		errMsg = processErr.Error()
+	}
+
+	if err := s.deliveryRepo.Finish(ctx, deliveryID, status, errMsg); err != nil {
+		logger.LogError(errors.New("FINISH_DELIVERY_ERROR", "error recording webhook delivery outcome", err, errors.Critical))
+	}
+	if processErr != nil {
+		logger.LogError(errors.New("PROCESS_DELIVERY_ERROR", fmt.Sprintf("error processing %s delivery %s", event, deliveryID), processErr, errors.Critical))
+		return true, processErr
+	}
+
+	logger.LogInfo(fmt.Sprintf("Webhook delivery %s (%s) %s", deliveryID, event, status))
+	return true, nil
+}
+
+func (s *webhookService) process(ctx context.Context, event string, payload []byte) (string, error) {
+	switch event {
+	case github.EventPush:
+		var push github.PushEvent
+		if err := json.Unmarshal(payload, &push); err != nil {
+			return "", errors.New("DECODE_PAYLOAD_ERROR", "invalid push payload", err, errors.Warning)
+		}
+		return s.handlePush(ctx, &push)
+	case github.EventRepository:
+		var repoEvent github.RepositoryEvent
+		if err := json.Unmarshal(payload, &repoEvent); err != nil {
+			return "", errors.New("DECODE_PAYLOAD_ERROR", "invalid repository payload", err, errors.Warning)
+		}
+		return s.handleRepository(ctx, &repoEvent)
+	default:
+		return domain.WebhookStatusIgnored, nil
+	}
+}
+
+// handlePush saves the commits pushed to a tracked branch of a monitored repository. Forced
+// pushes are left to polling, which detects the rewritten history.
+func (s *webhookService) handlePush(ctx context.Context, push *github.PushEvent) (string, error) {
+	owner := push.Repository.Owner.OwnerLogin()
+	repo, err := s.repositoryService.GetRepository(ctx, push.Repository.Name, owner)
+	if err != nil {
+		return "", err
+	}
+	if repo == nil || !repo.IsMonitored() || !strings.HasPrefix(push.Ref, branchRefPrefix) || len(push.Commits) == 0 {
+		return domain.WebhookStatusIgnored, nil
+	}
+	branch := strings.TrimPrefix(push.Ref, branchRefPrefix)
+	repo.DefaultBranch = push.Repository.DefaultBranch
+	if !repo.TracksBranch(branch) {
+		return domain.WebhookStatusIgnored, nil
+	}
+	if push.Forced {
+		// Saving the new head would hide the rewrite from the next poll, which detects it.
+		logger.LogInfo(fmt.Sprintf("Leaving force-push to %s of %s/%s to polling", branch, owner, repo.Name))
+		return domain.WebhookStatusIgnored, nil
+	}
+
+	commits := make([]domain.Commit, len(push.Commits))
+	for i, commit := range push.Commits {
+		commits[i] = domain.Commit{
+			RepositoryID:   repo.ID,
+			Hash:           commit.ID,
+			Message:        commit.Message,
+			AuthorName:     commit.Author.Name,
+			AuthorEmail:    commit.Author.Email,
+			AuthorLogin:    commit.Author.Username,
+			CommitterName:  commit.Committer.Name,
+			CommitterEmail: commit.Committer.Email,
+			CommitterLogin: commit.Committer.Username,
+			CommitDate:     commit.Timestamp,
+			URL:            push.Repository.CommitAPIURL(commit.ID),
+			TreeSHA:        commit.TreeID,
+			HTMLURL:        commit.URL,
+			Branch:         branch,
+		}
+	}
+
+	if err := s.commitService.SaveCommits(ctx, commits); err != nil {
+		return "", err
+	}
+	return domain.WebhookStatusProcessed, nil
+}
+
+// handleRepository refreshes the stored metadata of a monitored repository, renaming it first
+// when the event reports a rename.
+func (s *webhookService) handleRepository(ctx context.Context, event *github.RepositoryEvent) (string, error) {
+	apiRepo := event.Repository
+	owner := apiRepo.Owner.OwnerLogin()
+	var existing *domain.Repository
+	var err error
+	if from := event.Changes.Repository.Name.From; event.Action == "renamed" && from != "" && from != apiRepo.Name {
+		existing, err = s.repositoryService.RenameRepository(ctx, owner, from, apiRepo.Name)
+	} else {
+		existing, err = s.repositoryService.GetRepository(ctx, apiRepo.Name, owner)
+	}
+	if err != nil {
+		return "", err
+	}
+	if existing == nil || !existing.IsMonitored() || event.Action == "deleted" {
+		return domain.WebhookStatusIgnored, nil
+	}
+
+	repo := &domain.Repository{
+		Owner:           owner,
+		Name:            apiRepo.Name,
+		Description:     apiRepo.Description,
+		URL:             apiRepo.URL,
+		Language:        apiRepo.Language,
+		ForksCount:      apiRepo.ForksCount,
+		StargazersCount: apiRepo.StargazersCount,
+		OpenIssuesCount: apiRepo.OpenIssuesCount,
+		WatchersCount:   apiRepo.WatchersCount,
+		CreatedAt:       apiRepo.CreatedAt,
+		UpdatedAt:       apiRepo.UpdatedAt,
+		DefaultBranch:   apiRepo.DefaultBranch,
+	}
+	if err := s.repositoryService.UpsertRepository(ctx, repo); err != nil {
+		return "", err
+	}
+	return domain.WebhookStatusProcessed, nil
+}
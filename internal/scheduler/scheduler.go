@@ -2,53 +2,204 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron"
 	"github.com/olusolaa/github-monitor/config"
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/internal/core/services"
 	"github.com/olusolaa/github-monitor/pkg/logger"
+	"github.com/olusolaa/github-monitor/pkg/utils"
 )
 
 type Scheduler struct {
 	monitorService *services.MonitorService
 	cfg            *config.Config
-	schedulers     map[int64]*gocron.Scheduler // Map to track schedulers by repo ID
+	schedulers     map[int64]*monitoringJob // Map to track schedulers by repo ID
+	rescheduleChan chan int64
+	mu             sync.Mutex
 }
 
-func NewScheduler(monitorService *services.MonitorService, cfg *config.Config) *Scheduler {
+// monitoringJob is the scheduler polling a repository. Its runs share a context that is cancelled
+// when it stops, so stopping does not wait out a run sleeping through a rate limit.
+type monitoringJob struct {
+	scheduler *gocron.Scheduler
+	cancel    context.CancelFunc
+}
+
+// stop cancels the running poll, if any, and stops the scheduler once it has returned.
+func (j *monitoringJob) stop() {
+	j.cancel()
+	j.scheduler.Stop()
+}
+
+// NewScheduler creates a scheduler polling monitored repositories. Repositories whose adaptive
+// polling interval changes are published on rescheduleChan, like those whose schedule changes.
+func NewScheduler(monitorService *services.MonitorService, cfg *config.Config, rescheduleChan chan int64) *Scheduler {
 	return &Scheduler{
 		monitorService: monitorService,
 		cfg:            cfg,
-		schedulers:     make(map[int64]*gocron.Scheduler),
+		schedulers:     make(map[int64]*monitoringJob),
+		rescheduleChan: rescheduleChan,
 	}
 }
 
 func (s *Scheduler) ScheduleMonitoring(monitoringChan chan int64) {
 	for repoID := range monitoringChan {
 		logger.LogInfo(fmt.Sprintf("Monitoring scheduled for repository ID: %d", repoID))
+		s.mu.Lock()
 		if _, exists := s.schedulers[repoID]; !exists {
+			s.schedulerStart(gocron.NewScheduler(time.UTC), repoID)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// StopMonitoring stops polling the repositories received on removalChan and cancels their
+// unfinished backfills.
+func (s *Scheduler) StopMonitoring(removalChan chan int64) {
+	for repoID := range removalChan {
+		s.Unschedule(repoID)
+		if err := s.monitorService.StopRepository(context.Background(), repoID); err != nil {
+			logger.LogError(fmt.Errorf("failed to cancel backfill for repository ID %d: %w", repoID, err))
+		}
+	}
+}
+
+// RescheduleMonitoring applies the current schedule of the repositories received on
+// rescheduleChan, from the end of the current period. Repositories that are not monitored yet
+// pick it up once they are. The replaced scheduler is stopped without holding the lock, as it
+// waits for its running poll to return.
+func (s *Scheduler) RescheduleMonitoring(rescheduleChan chan int64) {
+	for repoID := range rescheduleChan {
+		s.mu.Lock()
+		existing, exists := s.schedulers[repoID]
+		if exists {
 			scheduler := gocron.NewScheduler(time.UTC)
-			s.schedulerJob(scheduler, repoID)
+			scheduler.WaitForScheduleAll()
 			s.schedulerStart(scheduler, repoID)
 		}
+		s.mu.Unlock()
+
+		if exists {
+			existing.stop()
+			logger.LogInfo(fmt.Sprintf("Monitoring rescheduled for repository ID: %d", repoID))
+		}
+	}
+}
+
+// Unschedule stops the monitoring job of a repository, if it has one. The job is removed under
+// the lock and stopped after releasing it, cancelling its running poll.
+func (s *Scheduler) Unschedule(repoID int64) {
+	s.mu.Lock()
+	job, exists := s.schedulers[repoID]
+	delete(s.schedulers, repoID)
+	s.mu.Unlock()
+
+	if exists {
+		job.stop()
+		logger.LogInfo(fmt.Sprintf("Monitoring stopped for repository ID: %d", repoID))
 	}
 }
 
-func (s *Scheduler) schedulerJob(scheduler *gocron.Scheduler, repoID int64) {
-	scheduler.Every(s.cfg.PollInterval).Do(func() {
-		s.monitorRepository(repoID)
-	})
+// schedulerJob polls a repository on its own schedule, falling back to the configured poll
+// interval when it has none or it cannot be used. With adaptive polling enabled, repositories
+// without a schedule are polled at an interval adapted to their activity instead.
+func (s *Scheduler) schedulerJob(ctx context.Context, scheduler *gocron.Scheduler, repoID int64) {
+	job := func() {
+		s.monitorRepository(ctx, repoID)
+	}
+
+	repository, err := s.monitorService.GetRepository(ctx, repoID)
+	if err != nil {
+		logger.LogError(fmt.Errorf("failed to load schedule for repository ID %d: %w", repoID, err))
+	}
+	if repository != nil && repository.Schedule != "" {
+		interval, cronExpr, err := utils.ParseSchedule(repository.Schedule)
+		if err == nil && cronExpr != "" {
+			_, err = scheduler.Cron(cronExpr).Do(job)
+		} else if err == nil {
+			_, err = scheduler.Every(interval).Do(job)
+		}
+		if err == nil {
+			return
+		}
+		logger.LogError(fmt.Errorf("invalid schedule %q for repository ID %d, using the poll interval: %w", repository.Schedule, repoID, err))
+		scheduler.Clear()
+	}
+
+	if s.cfg.AdaptivePolling && repository != nil {
+		s.adaptiveJob(ctx, scheduler, repository)
+		return
+	}
+	scheduler.Every(s.cfg.PollInterval).Do(job)
+}
+
+// adaptiveJob polls a repository starting from its last computed interval and adapts the
+// interval to the commit rate of the repository after every successful poll. A changed interval
+// is recorded and the repository is rescheduled with it. Overlapping runs are skipped.
+func (s *Scheduler) adaptiveJob(ctx context.Context, scheduler *gocron.Scheduler, repository *domain.Repository) {
+	repoID := repository.ID
+	interval := repository.PollInterval()
+	if interval <= 0 {
+		interval = s.cfg.PollInterval
+	}
+	interval = utils.ClampDuration(interval, s.cfg.MinPollInterval, s.cfg.MaxPollInterval)
+	emptyPolls := repository.EmptyPolls
+
+	var mu sync.Mutex
+	poll := func() {
+		if !mu.TryLock() {
+			return
+		}
+		defer mu.Unlock()
+
+		ctx := github.WithRateLimitWait(ctx)
+		recentCommits, err := s.monitorService.PollRepository(ctx, repoID, interval)
+		if errors.Is(err, services.ErrRepositoryPaused) {
+			return
+		}
+		if err != nil {
+			logger.LogError(fmt.Errorf("monitoring failed for repository ID %d: %w", repoID, err))
+			return
+		}
+
+		next, empty := utils.NextPollInterval(interval, emptyPolls, recentCommits, s.cfg.MinPollInterval, s.cfg.MaxPollInterval)
+		if err := s.monitorService.SavePollState(ctx, repoID, next, empty); err != nil {
+			logger.LogError(fmt.Errorf("failed to save poll state for repository ID %d: %w", repoID, err))
+			return
+		}
+		emptyPolls = empty
+		if next == interval || s.rescheduleChan == nil {
+			return
+		}
+
+		logger.LogInfo(fmt.Sprintf("Poll interval of repository ID %d changed from %s to %s", repoID, interval, next))
+		interval = next
+		// Rescheduling stops this scheduler, which waits for the poll to return first.
+		go func() { s.rescheduleChan <- repoID }()
+	}
+
+	if _, err := scheduler.Every(interval).Do(poll); err != nil {
+		logger.LogError(fmt.Errorf("failed to schedule repository ID %d every %s: %w", repoID, interval, err))
+	}
 }
 
+// schedulerStart schedules polling a repository on scheduler and starts it. The caller holds
+// the lock.
 func (s *Scheduler) schedulerStart(scheduler *gocron.Scheduler, repoID int64) {
-	s.schedulers[repoID] = scheduler
+	ctx, cancel := context.WithCancel(context.Background())
+	s.schedulerJob(ctx, scheduler, repoID)
+	s.schedulers[repoID] = &monitoringJob{scheduler: scheduler, cancel: cancel}
 	scheduler.StartAsync()
 }
 
-func (s *Scheduler) monitorRepository(repoID int64) {
-	ctx := context.Background()
+func (s *Scheduler) monitorRepository(ctx context.Context, repoID int64) {
+	ctx = github.WithRateLimitWait(ctx)
 	if err := s.monitorService.MonitorRepository(ctx, repoID); err != nil {
 		logger.LogError(fmt.Errorf("monitoring failed for repository ID %d: %w", repoID, err))
 	}
@@ -2,11 +2,15 @@ package httpclient
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 )
 
+// ErrNotModified is returned when the server answers a conditional request with 304 Not Modified.
+var ErrNotModified = errors.New("not modified")
+
 // ResponseHandler decodes and processes HTTP responses.
 type ResponseHandler struct{}
 
@@ -17,9 +21,14 @@ func NewResponseHandler() *ResponseHandler {
 
 // HandleResponse decodes the HTTP response body into the provided interface.
 // It also handles errors and extracts detailed error information if available.
+// A 304 response yields ErrNotModified and leaves out untouched.
 func (rh *ResponseHandler) HandleResponse(resp *http.Response, out interface{}) error {
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return ErrNotModified
+	}
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return rh.extractError(resp)
 	}
@@ -0,0 +1,154 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor is a position in a list paginated by keyset rather than offset: the sort key of an
+// item, such as the date and ID of a commit. A forward cursor continues after the item and a
+// backward one before it. Clients only see it encoded.
+type Cursor struct {
+	Time     time.Time `json:"t,omitempty"`
+	ID       int64     `json:"i,omitempty"`
+	Count    int       `json:"c,omitempty"`
+	Keys     []string  `json:"k,omitempty"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor returned by Encode. An empty value is the start of the list.
+func DecodeCursor(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	return &cursor, nil
+}
+
+// CursorParams selects a page of a list paginated by keyset.
+type CursorParams struct {
+	Cursor       *Cursor
+	PageSize     int
+	IncludeTotal bool
+}
+
+// Cursors holds the cursors of the pages around a page of a list paginated by keyset, empty
+// when there is no such page. TotalItems is only counted when asked for.
+type Cursors struct {
+	PageSize   int    `json:"page_size"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	TotalItems *int   `json:"total_items,omitempty"`
+}
+
+// ParseCursorParams parses keyset pagination parameters from URL query. Keyset pagination is
+// used when the cursor parameter is present, empty for the first page; nil is returned
+// otherwise. include_total=true counts the items of the list as well.
+func ParseCursorParams(query url.Values) (*CursorParams, error) {
+	if !query.Has("cursor") {
+		return nil, nil
+	}
+
+	cursor, err := DecodeCursor(query.Get("cursor"))
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	includeTotal, _ := strconv.ParseBool(query.Get("include_total"))
+	return &CursorParams{Cursor: cursor, PageSize: pageSize, IncludeTotal: includeTotal}, nil
+}
+
+// Backward reports whether the page precedes the cursor.
+func (p *CursorParams) Backward() bool {
+	return p.Cursor != nil && p.Cursor.Backward
+}
+
+// KeysetPage turns the rows fetched for a page, in scan order and with one extra row telling
+// whether the scan goes on, into the page in list order and its cursors. Rows are scanned
+// backwards from a backward cursor. key returns the position of a row.
+func KeysetPage[T any](rows []T, params *CursorParams, key func(T) Cursor) ([]T, *Cursors) {
+	backward := params.Backward()
+	more := len(rows) > params.PageSize
+	if more {
+		rows = rows[:params.PageSize]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	cursors := &Cursors{PageSize: params.PageSize}
+	if len(rows) == 0 {
+		return rows, cursors
+	}
+	if (backward && more) || (!backward && params.Cursor != nil) {
+		prev := key(rows[0])
+		prev.Backward = true
+		cursors.Prev = prev.Encode()
+	}
+	if (!backward && more) || backward {
+		cursors.Next = key(rows[len(rows)-1]).Encode()
+	}
+	return rows, cursors
+}
+
+// CursorLinks returns an RFC 5988 Link header value pointing to the next and previous pages of
+// the list requested with u, or an empty string when there are none.
+func CursorLinks(u *url.URL, cursors *Cursors) string {
+	var links []string
+	for _, link := range []struct{ rel, cursor string }{{"next", cursors.Next}, {"prev", cursors.Prev}} {
+		if link.cursor == "" {
+			continue
+		}
+		query := u.Query()
+		query.Set("cursor", link.cursor)
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, query.Encode(), link.rel))
+	}
+	return strings.Join(links, ", ")
+}
+
+// PageLinks returns an RFC 5988 Link header value pointing to the first, previous, next and
+// last pages of the list requested with u.
+func PageLinks(u *url.URL, pg *Pagination) string {
+	pageLink := func(page int, rel string) string {
+		query := u.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("page_size", strconv.Itoa(pg.PageSize))
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, query.Encode(), rel)
+	}
+
+	links := []string{pageLink(1, "first")}
+	if pg.Page > 1 {
+		links = append(links, pageLink(pg.Page-1, "prev"))
+	}
+	if pg.Page < pg.TotalPages {
+		links = append(links, pageLink(pg.Page+1, "next"))
+	}
+	if pg.TotalPages > 0 {
+		links = append(links, pageLink(pg.TotalPages, "last"))
+	}
+	return strings.Join(links, ", ")
+}
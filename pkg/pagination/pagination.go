@@ -6,9 +6,10 @@ import (
 	"strconv"
 )
 
-// PagedResponse wraps data with pagination info
+// PagedResponse wraps data with pagination info, either by page or by cursor
 type PagedResponse struct {
-	Pagination *Pagination `json:"pagination"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Cursors    *Cursors    `json:"cursors,omitempty"`
 	Data       interface{} `json:"data"`
 }
 
@@ -0,0 +1,22 @@
+package utils
+
+import "time"
+
+// ParseDate parses an optional RFC 3339 time or YYYY-MM-DD date, which is taken as the end of
+// the day when endOfDay is set. An empty value yields nil.
+func ParseDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		if endOfDay {
+			date = date.Add(24*time.Hour - time.Nanosecond)
+		}
+		return &date, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ParseSchedule parses a polling schedule, which is either a duration such as "5m" or a
+// standard five-field cron expression such as "0 3 * * *". It returns the interval for a
+// duration and the expression itself for a cron schedule.
+func ParseSchedule(schedule string) (time.Duration, string, error) {
+	if interval, err := time.ParseDuration(schedule); err == nil {
+		if interval <= 0 {
+			return 0, "", fmt.Errorf("schedule interval must be positive: %s", schedule)
+		}
+		return interval, "", nil
+	}
+
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return 0, "", fmt.Errorf("schedule must be a duration or a cron expression: %w", err)
+	}
+	return 0, schedule, nil
+}
+
+// EmptyPollsBeforeBackoff is the number of consecutive polls without new commits after which
+// NextPollInterval doubles the polling interval.
+const EmptyPollsBeforeBackoff = 3
+
+// NextPollInterval adapts a polling interval to the commit rate of a repository, given as the
+// number of commits made during the last interval. When there were any, the interval is
+// shortened to the average time between them, and at least halved. It is doubled once
+// EmptyPollsBeforeBackoff intervals in a row saw none, always staying within min and max. It
+// returns the new interval together with the updated count of consecutive empty polls.
+func NextPollInterval(current time.Duration, emptyPolls, recentCommits int, min, max time.Duration) (time.Duration, int) {
+	next := current
+	if recentCommits > 0 {
+		divisor := recentCommits
+		if divisor < 2 {
+			divisor = 2
+		}
+		next /= time.Duration(divisor)
+		emptyPolls = 0
+	} else if emptyPolls++; emptyPolls >= EmptyPollsBeforeBackoff {
+		next *= 2
+		emptyPolls = 0
+	}
+	return ClampDuration(next, min, max), emptyPolls
+}
+
+// ClampDuration limits d to the range [min, max].
+func ClampDuration(d, min, max time.Duration) time.Duration {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
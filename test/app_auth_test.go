@@ -0,0 +1,178 @@
+package test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/pkg/httpclient"
+)
+
+// fakeAppServer serves the app installation endpoints and a repository endpoint that
+// echoes back the token it was called with.
+func fakeAppServer(t *testing.T, key *rsa.PrivateKey, tokenTTL time.Duration, mints *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		switch {
+		case r.URL.Path == "/app/installations":
+			verifyAppJWT(t, &key.PublicKey, auth)
+			w.Write([]byte(`[{"id": 42, "account": {"login": "Owner"}}]`))
+		case r.URL.Path == "/app/installations/42/access_tokens" && r.Method == http.MethodPost:
+			verifyAppJWT(t, &key.PublicKey, auth)
+			n := atomic.AddInt32(mints, 1)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token":      fmt.Sprintf("installation-token-%d", n),
+				"expires_at": time.Now().Add(tokenTTL).UTC().Format(time.RFC3339),
+			})
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/"):
+			w.Write([]byte(auth))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func verifyAppJWT(t *testing.T, pub *rsa.PublicKey, jwt string) {
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature))
+
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(claims), `"iss":123`)
+}
+
+func writePrivateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "app.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return key, path
+}
+
+func getAuthorization(t *testing.T, client *httpclient.Client, url string) string {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestAppAuthenticator_CachesInstallationToken(t *testing.T) {
+	key, keyPath := writePrivateKey(t)
+	var mints int32
+	server := fakeAppServer(t, key, time.Hour, &mints)
+	defer server.Close()
+
+	auth, err := github.NewAppAuthenticator(server.URL, http.DefaultClient, 123, keyPath, 0)
+	require.NoError(t, err)
+	client := httpclient.NewClient(http.DefaultClient, auth.AuthMiddleware)
+
+	assert.Equal(t, "installation-token-1", getAuthorization(t, client, server.URL+"/repos/owner/repo"))
+	assert.Equal(t, "installation-token-1", getAuthorization(t, client, server.URL+"/repos/owner/repo/commits"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mints))
+}
+
+func TestAppAuthenticator_RefreshesTokenNearExpiry(t *testing.T) {
+	key, keyPath := writePrivateKey(t)
+	var mints int32
+	server := fakeAppServer(t, key, time.Minute, &mints)
+	defer server.Close()
+
+	auth, err := github.NewAppAuthenticator(server.URL, http.DefaultClient, 123, keyPath, 0)
+	require.NoError(t, err)
+	client := httpclient.NewClient(http.DefaultClient, auth.AuthMiddleware)
+
+	assert.Equal(t, "installation-token-1", getAuthorization(t, client, server.URL+"/repos/owner/repo"))
+	assert.Equal(t, "installation-token-2", getAuthorization(t, client, server.URL+"/repos/owner/repo"))
+}
+
+func TestAppAuthenticator_UnknownOwnerWithoutDefault(t *testing.T) {
+	key, keyPath := writePrivateKey(t)
+	var mints int32
+	server := fakeAppServer(t, key, time.Hour, &mints)
+	defer server.Close()
+
+	auth, err := github.NewAppAuthenticator(server.URL, http.DefaultClient, 123, keyPath, 0)
+	require.NoError(t, err)
+	client := httpclient.NewClient(http.DefaultClient, auth.AuthMiddleware)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/repos/stranger/repo", nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.Error(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&mints))
+}
+
+func TestAppAuthenticator_SharesConcurrentMints(t *testing.T) {
+	key, keyPath := writePrivateKey(t)
+	var mints int32
+	server := fakeAppServer(t, key, time.Hour, &mints)
+	defer server.Close()
+
+	auth, err := github.NewAppAuthenticator(server.URL, http.DefaultClient, 123, keyPath, 0)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := auth.InstallationToken(context.Background(), "owner")
+			assert.NoError(t, err)
+			assert.Equal(t, "installation-token-1", token)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mints))
+}
+
+func TestAppAuthenticator_FallsBackWhenListingFails(t *testing.T) {
+	key, keyPath := writePrivateKey(t)
+	var mints int32
+	appServer := fakeAppServer(t, key, time.Hour, &mints)
+	defer appServer.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/installations" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		appServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	auth, err := github.NewAppAuthenticator(server.URL, http.DefaultClient, 123, keyPath, 42)
+	require.NoError(t, err)
+
+	token, err := auth.InstallationToken(context.Background(), "stranger")
+	assert.NoError(t, err)
+	assert.Equal(t, "installation-token-1", token)
+}
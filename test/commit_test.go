@@ -2,13 +2,17 @@ package test
 
 import (
 	"context"
+	"errors"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/internal/core/services"
 	"github.com/olusolaa/github-monitor/pkg/pagination"
@@ -19,9 +23,10 @@ type MockGitHubService struct{ mock.Mock }
 
 type MockRepositoryService struct{ mock.Mock }
 type MockCommitRepository struct{ mock.Mock }
+type MockBackfillJobRepository struct{ mock.Mock }
 
-func (m *MockGitHubService) FetchCommits(ctx context.Context, owner, name, startDate, endDate string, repoID int64, domainCommitsChan chan<- []domain.Commit, errChan chan<- error) {
-	m.Called(ctx, owner, name, startDate, endDate, repoID, domainCommitsChan, errChan)
+func (m *MockGitHubService) FetchCommits(ctx context.Context, owner, name string, opts github.CommitListOptions, repoID int64, domainCommitsChan chan<- []domain.Commit, errChan chan<- error) {
+	m.Called(ctx, owner, name, opts, repoID, domainCommitsChan, errChan)
 }
 
 func (m *MockGitHubService) FetchRepository(ctx context.Context, owner string, repoName string) (*domain.Repository, error) {
@@ -29,6 +34,31 @@ func (m *MockGitHubService) FetchRepository(ctx context.Context, owner string, r
 	return args.Get(0).(*domain.Repository), args.Error(1)
 }
 
+func (m *MockGitHubService) ListRepositories(ctx context.Context, owner string, filter domain.RepositoryFilter) ([]domain.Repository, error) {
+	args := m.Called(ctx, owner, filter)
+	return args.Get(0).([]domain.Repository), args.Error(1)
+}
+
+func (m *MockGitHubService) ListBranches(ctx context.Context, owner, repoName string) ([]string, error) {
+	args := m.Called(ctx, owner, repoName)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockGitHubService) CompareCommits(ctx context.Context, owner, repoName, base, head string) (*github.Comparison, error) {
+	args := m.Called(ctx, owner, repoName, base, head)
+	return args.Get(0).(*github.Comparison), args.Error(1)
+}
+
+func (m *MockGitHubService) GetBranchHead(ctx context.Context, owner, repoName, branch string) (string, error) {
+	args := m.Called(ctx, owner, repoName, branch)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGitHubService) FetchCommitChanges(ctx context.Context, owner, repoName, sha string) (*domain.CommitChanges, error) {
+	args := m.Called(ctx, owner, repoName, sha)
+	return args.Get(0).(*domain.CommitChanges), args.Error(1)
+}
+
 func (m *MockRepositoryService) GetOwnerAndRepoName(ctx context.Context, repoID int64) (string, string, error) {
 	args := m.Called(ctx, repoID)
 	return args.String(0), args.String(1), args.Error(2)
@@ -49,11 +79,61 @@ func (m *MockRepositoryService) AddRepository(owner string, repo string) error {
 	return args.Error(0)
 }
 
-func (m *MockRepositoryService) FetchRepository(ctx context.Context, owner string, repo string, commitChan chan int64) error {
-	args := m.Called(ctx, owner, repo, commitChan)
+func (m *MockRepositoryService) RemoveRepository(ctx context.Context, owner, repo string, purge bool) error {
+	args := m.Called(ctx, owner, repo, purge)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryService) PauseRepository(ctx context.Context, owner, repo string) error {
+	args := m.Called(ctx, owner, repo)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryService) ResumeRepository(ctx context.Context, owner, repo string) error {
+	args := m.Called(ctx, owner, repo)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryService) SetSchedule(ctx context.Context, owner, repo, schedule string) error {
+	args := m.Called(ctx, owner, repo, schedule)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryService) SetBranches(ctx context.Context, owner, repo string, branches []string) error {
+	args := m.Called(ctx, owner, repo, branches)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryService) RenameRepository(ctx context.Context, owner, oldName, newName string) (*domain.Repository, error) {
+	args := m.Called(ctx, owner, oldName, newName)
+	return args.Get(0).(*domain.Repository), args.Error(1)
+}
+
+func (m *MockRepositoryService) UpdatePollState(ctx context.Context, repoID int64, interval time.Duration, emptyPolls int) error {
+	args := m.Called(ctx, repoID, interval, emptyPolls)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryService) RestoreMonitoring(ctx context.Context, commitChan chan int64) error {
+	args := m.Called(ctx, commitChan)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryService) Reconcile(ctx context.Context, declared []domain.Repository) error {
+	args := m.Called(ctx, declared)
 	return args.Error(0)
 }
 
+func (m *MockRepositoryService) StopOwnerRepositories(ctx context.Context, owner string) (int, error) {
+	args := m.Called(ctx, owner)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepositoryService) FetchRepository(ctx context.Context, owner string, repo string, commitChan chan int64) (*domain.Repository, error) {
+	args := m.Called(ctx, owner, repo, commitChan)
+	return args.Get(0).(*domain.Repository), args.Error(1)
+}
+
 func (m *MockCommitRepository) Save(ctx context.Context, commits []domain.Commit) error {
 	args := m.Called(ctx, commits)
 	return args.Error(0)
@@ -64,13 +144,80 @@ func (m *MockCommitRepository) GetLatestCommitByRepositoryID(ctx context.Context
 	return args.Get(0).(*domain.Commit), args.Error(1)
 }
 
-func (m *MockCommitRepository) GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]domain.Commit, int, error) {
-	args := m.Called(ctx, owner, name, page, pageSize)
+func (m *MockCommitRepository) GetLatestCommitByBranch(ctx context.Context, repoID int64, branch string) (*domain.Commit, error) {
+	args := m.Called(ctx, repoID, branch)
+	return args.Get(0).(*domain.Commit), args.Error(1)
+}
+
+func (m *MockCommitRepository) GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, int, error) {
+	args := m.Called(ctx, owner, name, filter, page, pageSize)
 	return args.Get(0).([]domain.Commit), args.Int(1), args.Error(2)
 }
 
-func (m *MockCommitRepository) GetTopCommitAuthors(ctx context.Context, repoID int64, limit int) ([]domain.CommitAuthor, error) {
-	args := m.Called(ctx, repoID, limit)
+func (m *MockCommitRepository) GetCommitsByCursor(ctx context.Context, owner, name string, filter domain.CommitFilter, cursor *pagination.Cursor, limit int) ([]domain.Commit, error) {
+	args := m.Called(ctx, owner, name, filter, cursor, limit)
+	return args.Get(0).([]domain.Commit), args.Error(1)
+}
+
+func (m *MockCommitRepository) CountCommits(ctx context.Context, owner, name string, filter domain.CommitFilter) (int, error) {
+	args := m.Called(ctx, owner, name, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommitRepository) CountCommitsSince(ctx context.Context, repoID int64, since time.Time) (int, error) {
+	args := m.Called(ctx, repoID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommitRepository) StreamCommits(ctx context.Context, owner, name string, filter domain.CommitFilter, handleBatch func([]domain.Commit) error) error {
+	args := m.Called(ctx, owner, name, filter, handleBatch)
+	if batches, ok := args.Get(0).([][]domain.Commit); ok {
+		for _, batch := range batches {
+			if err := handleBatch(batch); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockCommitRepository) GetTopCommitAuthorsByCursor(ctx context.Context, owner, name string, filter domain.AuthorFilter, cursor *pagination.Cursor, limit int) ([]domain.CommitAuthor, error) {
+	args := m.Called(ctx, owner, name, filter, cursor, limit)
+	return args.Get(0).([]domain.CommitAuthor), args.Error(1)
+}
+
+func (m *MockCommitRepository) GetCommitActivity(ctx context.Context, owner, name, interval string, since, until time.Time) ([]domain.ActivityBucket, error) {
+	args := m.Called(ctx, owner, name, interval, since, until)
+	return args.Get(0).([]domain.ActivityBucket), args.Error(1)
+}
+
+func (m *MockCommitRepository) GetCommitActivityByAuthor(ctx context.Context, owner, name, interval string, since, until time.Time) ([]domain.AuthorActivity, error) {
+	args := m.Called(ctx, owner, name, interval, since, until)
+	return args.Get(0).([]domain.AuthorActivity), args.Error(1)
+}
+
+func (m *MockCommitRepository) GetPunchCard(ctx context.Context, owner, name string, since, until time.Time) ([]domain.PunchCardEntry, error) {
+	args := m.Called(ctx, owner, name, since, until)
+	return args.Get(0).([]domain.PunchCardEntry), args.Error(1)
+}
+
+func (m *MockCommitRepository) CountCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter) (int, error) {
+	args := m.Called(ctx, owner, name, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommitRepository) RecordHistoryRewrite(ctx context.Context, rewrite *domain.HistoryRewrite, since time.Time, reachable []string) (*time.Time, error) {
+	args := m.Called(ctx, rewrite, since, reachable)
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockCommitRepository) GetHistoryRewrites(ctx context.Context, owner, name string) ([]domain.HistoryRewrite, error) {
+	args := m.Called(ctx, owner, name)
+	return args.Get(0).([]domain.HistoryRewrite), args.Error(1)
+}
+
+func (m *MockCommitRepository) GetTopCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error) {
+	args := m.Called(ctx, owner, name, filter, limit)
 	return args.Get(0).([]domain.CommitAuthor), args.Error(1)
 }
 
@@ -84,6 +231,41 @@ func (m *MockCommitRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	return args.Get(0).(*sqlx.Tx), args.Error(1)
 }
 
+func (m *MockBackfillJobRepository) Create(ctx context.Context, job *domain.BackfillJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockBackfillJobRepository) SaveCheckpoint(ctx context.Context, jobID int64, branch string, completedBranches []string, lastPage, commitsSaved int, lastCommitDate *time.Time) error {
+	args := m.Called(ctx, jobID, branch, completedBranches, lastPage, commitsSaved, lastCommitDate)
+	return args.Error(0)
+}
+
+func (m *MockBackfillJobRepository) UpdateStatus(ctx context.Context, jobID int64, status, errMsg string) error {
+	args := m.Called(ctx, jobID, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockBackfillJobRepository) FindByID(ctx context.Context, jobID int64) (*domain.BackfillJob, error) {
+	args := m.Called(ctx, jobID)
+	return args.Get(0).(*domain.BackfillJob), args.Error(1)
+}
+
+func (m *MockBackfillJobRepository) FindUnfinished(ctx context.Context) ([]domain.BackfillJob, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.BackfillJob), args.Error(1)
+}
+
+func (m *MockBackfillJobRepository) FindUnfinishedByRepositoryID(ctx context.Context, repoID int64) (*domain.BackfillJob, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).(*domain.BackfillJob), args.Error(1)
+}
+
+func (m *MockBackfillJobRepository) FindCompletedByRepositoryID(ctx context.Context, repoID int64) (*domain.BackfillJob, error) {
+	args := m.Called(ctx, repoID)
+	return args.Get(0).(*domain.BackfillJob), args.Error(1)
+}
+
 // Test cases
 func TestCommitService_SaveCommits(t *testing.T) {
 	mockGitHubService := new(MockGitHubService)
@@ -91,7 +273,7 @@ func TestCommitService_SaveCommits(t *testing.T) {
 	mockCommitRepo := new(MockCommitRepository)
 	mockCommitChan := make(chan int64)
 
-	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, mockCommitChan)
+	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), mockCommitChan, make(chan int64), "", "")
 
 	commits := []domain.Commit{{RepositoryID: 1, Hash: "hash1", Message: "Commit message", CommitDate: time.Now()}}
 
@@ -109,7 +291,7 @@ func TestCommitService_GetLatestCommit(t *testing.T) {
 	mockCommitRepo := new(MockCommitRepository)
 	mockCommitChan := make(chan int64)
 
-	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, mockCommitChan)
+	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), mockCommitChan, make(chan int64), "", "")
 
 	expectedCommit := &domain.Commit{RepositoryID: 1, Hash: "hash1", Message: "Commit message", CommitDate: time.Now()}
 
@@ -128,14 +310,14 @@ func TestCommitService_GetCommitsByRepositoryName(t *testing.T) {
 	mockCommitRepo := new(MockCommitRepository)
 	mockCommitChan := make(chan int64)
 
-	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, mockCommitChan)
+	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), mockCommitChan, make(chan int64), "", "")
 
 	expectedCommits := []domain.Commit{{RepositoryID: 1, Hash: "hash1", Message: "Commit message", CommitDate: time.Now()}}
 	totalItems := 1
 
-	mockCommitRepo.On("GetCommitsByRepositoryName", mock.Anything, "owner", "name", 1, 10).Return(expectedCommits, totalItems, nil)
+	mockCommitRepo.On("GetCommitsByRepositoryName", mock.Anything, "owner", "name", domain.CommitFilter{}, 1, 10).Return(expectedCommits, totalItems, nil)
 
-	commits, pg, err := service.GetCommitsByRepositoryName(context.Background(), "owner", "name", 1, 10)
+	commits, pg, err := service.GetCommitsByRepositoryName(context.Background(), "owner", "name", domain.CommitFilter{}, 1, 10)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCommits, commits)
@@ -143,47 +325,211 @@ func TestCommitService_GetCommitsByRepositoryName(t *testing.T) {
 	mockCommitRepo.AssertExpectations(t)
 }
 
+func TestCommitService_GetCommitsByRepositoryNameFiltered(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	since := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	merge := false
+	filter := domain.CommitFilter{Author: "jane@example.com", Since: &since, Query: "fix", Path: "/internal/core/", Merge: &merge, Sort: domain.CommitSortOldest}
+	expectedFilter := filter
+	expectedFilter.Path = "internal/core"
+	mockCommitRepo.On("GetCommitsByRepositoryName", mock.Anything, "owner", "name", expectedFilter, 1, 10).Return([]domain.Commit{}, 0, nil)
+
+	_, _, err := service.GetCommitsByRepositoryName(context.Background(), "owner", "name", filter, 1, 10)
+
+	assert.NoError(t, err)
+	mockCommitRepo.AssertExpectations(t)
+}
+
+func TestCommitService_GetCommitsByRepositoryNameInvalidFilter(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	since := time.Date(2024, 8, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	for _, filter := range []domain.CommitFilter{{Sort: "author"}, {Since: &since, Until: &until}} {
+		_, _, err := service.GetCommitsByRepositoryName(context.Background(), "owner", "name", filter, 1, 10)
+		assert.Error(t, err)
+	}
+	mockCommitRepo.AssertNotCalled(t, "GetCommitsByRepositoryName", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommitService_GetCommitsByCursor(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	date := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	rows := []domain.Commit{{ID: 3, CommitDate: date.Add(2 * time.Hour)}, {ID: 2, CommitDate: date.Add(time.Hour)}, {ID: 1, CommitDate: date}}
+	mockCommitRepo.On("GetCommitsByCursor", mock.Anything, "owner", "name", domain.CommitFilter{}, (*pagination.Cursor)(nil), 3).Return(rows, nil)
+	mockCommitRepo.On("CountCommits", mock.Anything, "owner", "name", domain.CommitFilter{}).Return(7, nil)
+
+	commits, cursors, err := service.GetCommitsByCursor(context.Background(), "owner", "name", domain.CommitFilter{}, &pagination.CursorParams{PageSize: 2, IncludeTotal: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, rows[:2], commits)
+	assert.Empty(t, cursors.Prev)
+	assert.Equal(t, 7, *cursors.TotalItems)
+	next, err := pagination.DecodeCursor(cursors.Next)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), next.ID)
+	assert.True(t, date.Add(time.Hour).Equal(next.Time))
+}
+
+func TestCommitService_GetCommitsByCursorRejectsAuthorCursor(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	cursor := &pagination.Cursor{Count: 3, Keys: []string{"Jane", "jane@example.com"}}
+	_, _, err := service.GetCommitsByCursor(context.Background(), "owner", "name", domain.CommitFilter{}, &pagination.CursorParams{Cursor: cursor, PageSize: 10})
+
+	assert.Error(t, err)
+	mockCommitRepo.AssertNotCalled(t, "GetCommitsByCursor", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestCommitService_GetTopCommitAuthors(t *testing.T) {
 	mockGitHubService := new(MockGitHubService)
 	mockRepoService := new(MockRepositoryService)
 	mockCommitRepo := new(MockCommitRepository)
 	mockCommitChan := make(chan int64)
 
-	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, mockCommitChan)
+	service := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), mockCommitChan, make(chan int64), "", "")
 
 	expectedAuthors := []domain.CommitAuthor{
 		{AuthorName: "John Doe", AuthorEmail: "john@example.com", CommitCount: 5},
 		{AuthorName: "Jane Doe", AuthorEmail: "jane@example.com", CommitCount: 3},
 	}
 
-	mockCommitRepo.On("GetTopCommitAuthors", mock.Anything, int64(1), 2).Return(expectedAuthors, nil)
+	mockCommitRepo.On("GetTopCommitAuthors", mock.Anything, "owner", "name", domain.AuthorFilter{}, 2).Return(expectedAuthors, nil)
 
-	authors, err := service.GetTopCommitAuthors(context.Background(), 1, 2)
+	authors, err := service.GetTopCommitAuthors(context.Background(), "owner", "name", domain.AuthorFilter{}, 2)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedAuthors, authors)
 	mockCommitRepo.AssertExpectations(t)
 }
 
+func TestCommitService_GetTopCommitAuthorsAcrossRepositories(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	since := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	filter := domain.AuthorFilter{Owners: []string{"acme"}, Labels: []string{"backend"}, Since: &since, ExcludeBots: true}
+	rows := []domain.CommitAuthor{
+		{AuthorName: "Jane Doe", AuthorEmail: "jane@example.com", CommitCount: 5, ActiveDays: 3, Repositories: 2},
+		{AuthorName: "John Doe", AuthorEmail: "john@example.com", CommitCount: 3, ActiveDays: 1, Repositories: 1},
+		{AuthorName: "Max Doe", AuthorEmail: "max@example.com", CommitCount: 1, ActiveDays: 1, Repositories: 1},
+	}
+	mockCommitRepo.On("GetTopCommitAuthorsByCursor", mock.Anything, "", "", filter, (*pagination.Cursor)(nil), 3).Return(rows, nil)
+	mockCommitRepo.On("CountCommitAuthors", mock.Anything, "", "", filter).Return(3, nil)
+
+	authors, cursors, err := service.GetTopCommitAuthorsByCursor(context.Background(), "", "", filter, &pagination.CursorParams{PageSize: 2, IncludeTotal: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, rows[:2], authors)
+	assert.NotEmpty(t, cursors.Next)
+	assert.Equal(t, 3, *cursors.TotalItems)
+	mockCommitRepo.AssertExpectations(t)
+}
+
+func TestCommitService_GetTopCommitAuthorsInvalidWindow(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	since := time.Date(2024, 8, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	_, err := service.GetTopCommitAuthors(context.Background(), "", "", domain.AuthorFilter{Since: &since, Until: &until}, 10)
+
+	assert.Error(t, err)
+	mockCommitRepo.AssertNotCalled(t, "GetTopCommitAuthors", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommitService_GetCommitActivityByAuthor(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	since := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 8, 3, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 8, d, 0, 0, 0, 0, time.UTC) }
+
+	mockRepoService.On("GetRepository", mock.Anything, "name", "owner").Return(&domain.Repository{ID: 1, Owner: "owner", Name: "name"}, nil)
+	mockCommitRepo.On("GetCommitActivity", mock.Anything, "owner", "name", domain.ActivityIntervalDay, since, until).Return([]domain.ActivityBucket{
+		{Start: day(1), Commits: 3},
+		{Start: day(2), Commits: 0},
+		{Start: day(3), Commits: 1},
+	}, nil)
+	mockCommitRepo.On("GetCommitActivityByAuthor", mock.Anything, "owner", "name", domain.ActivityIntervalDay, since, until).Return([]domain.AuthorActivity{
+		{Bucket: day(1), AuthorEmail: "jane@example.com", Commits: 2},
+		{Bucket: day(1), AuthorEmail: "john@example.com", Commits: 1},
+		{Bucket: day(3), AuthorEmail: "jane@example.com", Commits: 1},
+	}, nil)
+
+	buckets, err := service.GetCommitActivity(context.Background(), "owner", "name", domain.ActivityQuery{Since: &since, Until: &until, ByAuthor: true})
+
+	assert.NoError(t, err)
+	if assert.Len(t, buckets, 3) {
+		assert.Len(t, buckets[0].Authors, 2)
+		assert.Empty(t, buckets[1].Authors)
+		assert.Equal(t, "jane@example.com", buckets[2].Authors[0].AuthorEmail)
+	}
+	mockCommitRepo.AssertExpectations(t)
+}
+
+func TestCommitService_GetCommitActivityInvalidInterval(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+
+	_, err := service.GetCommitActivity(context.Background(), "owner", "name", domain.ActivityQuery{Interval: "hour"})
+
+	assert.Error(t, err)
+	mockCommitRepo.AssertNotCalled(t, "GetCommitActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestActivityQuery_Normalize(t *testing.T) {
+	now := time.Date(2024, 8, 31, 12, 0, 0, 0, time.UTC)
+
+	query := domain.ActivityQuery{Interval: domain.ActivityIntervalWeek}
+	assert.NoError(t, query.Normalize(now))
+	assert.Equal(t, now, *query.Until)
+	assert.Equal(t, now.AddDate(0, 0, -26*7), *query.Since)
+
+	since := now.AddDate(-10, 0, 0)
+	query = domain.ActivityQuery{Since: &since}
+	assert.Error(t, query.Normalize(now), "ten years of daily buckets exceed the limit")
+
+	until := now.AddDate(0, 0, -1)
+	query = domain.ActivityQuery{Since: &now, Until: &until}
+	assert.Error(t, query.Normalize(now))
+}
+
 func TestCommitService_ProcessCommits(t *testing.T) {
 	mockGitHubService := new(MockGitHubService)
 	mockRepoService := new(MockRepositoryService)
 	mockCommitRepo := new(MockCommitRepository)
+	mockBackfillRepo := new(MockBackfillJobRepository)
 
 	repoID := int64(1)
 	owner := "testOwner"
 	name := "testRepo"
 	startDate := "2022-01-01"
 	endDate := "2022-01-31"
+	commitDate := time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC)
 	commits := []domain.Commit{
-		{Hash: "abc123", Message: "Initial commit"},
+		{Hash: "abc123", Message: "Initial commit", CommitDate: commitDate},
 	}
+	job := &domain.BackfillJob{ID: 10, RepositoryID: repoID, Since: startDate, Until: endDate, Status: domain.BackfillStatusRunning}
+	opts := github.CommitListOptions{Since: startDate, Until: endDate, Branch: "release"}
 
 	// Setup expectations
+	mockBackfillRepo.On("SaveCheckpoint", mock.Anything, int64(10), "release", []string(nil), 1, 1, &commitDate).Return(nil)
+	mockBackfillRepo.On("SaveCheckpoint", mock.Anything, int64(10), "release", []string{"release"}, 1, 1, &commitDate).Return(nil)
+	mockBackfillRepo.On("UpdateStatus", mock.Anything, int64(10), domain.BackfillStatusCompleted, "").Return(nil)
 	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, repoID).Return(owner, name, nil)
-	mockGitHubService.On("FetchCommits", mock.Anything, owner, name, startDate, endDate, repoID, mock.AnythingOfType("chan<- []domain.Commit"), mock.AnythingOfType("chan<- error")).Run(func(args mock.Arguments) {
-		commitsChan := args.Get(6).(chan<- []domain.Commit)
-		errChan := args.Get(7).(chan<- error)
+	mockRepoService.On("GetRepository", mock.Anything, name, owner).Return(&domain.Repository{ID: repoID, Owner: owner, Name: name, Branches: pq.StringArray{"release"}}, nil)
+	mockGitHubService.On("FetchCommits", mock.Anything, owner, name, opts, repoID, mock.AnythingOfType("chan<- []domain.Commit"), mock.AnythingOfType("chan<- error")).Run(func(args mock.Arguments) {
+		commitsChan := args.Get(5).(chan<- []domain.Commit)
+		errChan := args.Get(6).(chan<- error)
 
 		go func() {
 			commitsChan <- commits
@@ -195,13 +541,15 @@ func TestCommitService_ProcessCommits(t *testing.T) {
 
 	monitoringChan := make(chan int64, 1)
 
-	cs := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, monitoringChan)
+	cs := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, mockBackfillRepo, make(chan int64), monitoringChan, startDate, endDate)
 
-	cs.ProcessCommits(repoID, monitoringChan, startDate, endDate)
+	cs.ProcessCommits(context.Background(), job)
 
 	mockRepoService.AssertExpectations(t)
 	mockGitHubService.AssertExpectations(t)
 	mockCommitRepo.AssertExpectations(t)
+	mockBackfillRepo.AssertExpectations(t)
+	assert.Equal(t, domain.BackfillStatusCompleted, job.Status)
 
 	select {
 	case id := <-monitoringChan:
@@ -210,3 +558,197 @@ func TestCommitService_ProcessCommits(t *testing.T) {
 		t.Fatal("expected repoID in monitoringChan")
 	}
 }
+
+func TestCommitService_ProcessCommitsResumesAfterCheckpoint(t *testing.T) {
+	mockGitHubService := new(MockGitHubService)
+	mockRepoService := new(MockRepositoryService)
+	mockBackfillRepo := new(MockBackfillJobRepository)
+
+	repoID := int64(1)
+	lastCommitDate := time.Date(2023, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	job := &domain.BackfillJob{ID: 10, RepositoryID: repoID, Since: "2022-01-01", Status: domain.BackfillStatusRunning, LastPage: 4, CommitsSaved: 400, LastCommitDate: &lastCommitDate}
+	opts := github.CommitListOptions{Since: "2022-01-01", Until: "2023-06-01T10:00:00Z"}
+
+	mockBackfillRepo.On("UpdateStatus", mock.Anything, int64(10), domain.BackfillStatusFailed, "boom").Return(nil)
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, repoID).Return("owner", "name", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "name", "owner").Return(&domain.Repository{ID: repoID, Owner: "owner", Name: "name"}, nil)
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "name", opts, repoID, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		errChan := args.Get(6).(chan<- error)
+		go func() { errChan <- errors.New("boom") }()
+	}).Return(nil)
+
+	monitoringChan := make(chan int64, 1)
+	cs := services.NewCommitService(mockGitHubService, mockRepoService, new(MockCommitRepository), mockBackfillRepo, make(chan int64), monitoringChan, "2023-01-01", "")
+
+	cs.ProcessCommits(context.Background(), job)
+
+	mockGitHubService.AssertExpectations(t)
+	mockBackfillRepo.AssertExpectations(t)
+	assert.Equal(t, "boom", job.Error)
+	assert.Empty(t, monitoringChan)
+}
+
+func TestCommitService_ProcessCommitsResumesUncompletedBranches(t *testing.T) {
+	mockGitHubService := new(MockGitHubService)
+	mockRepoService := new(MockRepositoryService)
+	mockBackfillRepo := new(MockBackfillJobRepository)
+
+	repoID := int64(1)
+	// A branch matched since the checkpoint sorts before the branch being collected.
+	lastCommitDate := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	job := &domain.BackfillJob{ID: 10, RepositoryID: repoID, Since: "2022-01-01", Branch: "release", CompletedBranches: pq.StringArray{"main"}, Status: domain.BackfillStatusRunning, LastPage: 2, LastCommitDate: &lastCommitDate}
+
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, repoID).Return("owner", "name", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "name", "owner").Return(&domain.Repository{ID: repoID, Owner: "owner", Name: "name", Branches: pq.StringArray{"hotfix", "main", "release"}}, nil)
+	for _, opts := range []github.CommitListOptions{
+		{Since: "2022-01-01", Until: "2023-06-01T00:00:00Z", Branch: "release"},
+		{Since: "2022-01-01", Branch: "hotfix"},
+	} {
+		mockGitHubService.On("FetchCommits", mock.Anything, "owner", "name", opts, repoID, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			errChan := args.Get(6).(chan<- error)
+			go func() { errChan <- nil }()
+		}).Return(nil).Once()
+	}
+	mockBackfillRepo.On("SaveCheckpoint", mock.Anything, int64(10), "release", []string{"main", "release"}, 2, 0, &lastCommitDate).Return(nil)
+	mockBackfillRepo.On("SaveCheckpoint", mock.Anything, int64(10), "hotfix", []string{"main", "release", "hotfix"}, 0, 0, (*time.Time)(nil)).Return(nil)
+	mockBackfillRepo.On("UpdateStatus", mock.Anything, int64(10), domain.BackfillStatusCompleted, "").Return(nil)
+
+	cs := services.NewCommitService(mockGitHubService, mockRepoService, new(MockCommitRepository), mockBackfillRepo, make(chan int64), make(chan int64, 1), "", "")
+
+	cs.ProcessCommits(context.Background(), job)
+
+	mockGitHubService.AssertExpectations(t)
+	mockBackfillRepo.AssertExpectations(t)
+	assert.Equal(t, domain.BackfillStatusCompleted, job.Status)
+}
+
+func TestCommitService_CancelJob(t *testing.T) {
+	mockGitHubService := new(MockGitHubService)
+	mockRepoService := new(MockRepositoryService)
+	mockBackfillRepo := new(MockBackfillJobRepository)
+
+	repoID := int64(1)
+	cancelled := make(chan struct{})
+
+	mockBackfillRepo.On("FindUnfinishedByRepositoryID", mock.Anything, repoID).Return((*domain.BackfillJob)(nil), nil)
+	mockBackfillRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.BackfillJob")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.BackfillJob).ID = 10
+	}).Return(nil)
+	mockBackfillRepo.On("UpdateStatus", mock.Anything, int64(10), domain.BackfillStatusCancelled, "").Run(func(args mock.Arguments) {
+		close(cancelled)
+	}).Return(nil)
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, repoID).Return("owner", "name", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "name", "owner").Return(&domain.Repository{ID: repoID, Owner: "owner", Name: "name", StartDate: "2024-01-01"}, nil)
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "name", mock.Anything, repoID, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		errChan := args.Get(6).(chan<- error)
+		<-ctx.Done()
+		errChan <- ctx.Err()
+	}).Return(nil)
+
+	cs := services.NewCommitService(mockGitHubService, mockRepoService, new(MockCommitRepository), mockBackfillRepo, make(chan int64), make(chan int64, 1), "2022-01-01", "")
+
+	job, err := cs.StartBackfill(context.Background(), repoID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), job.ID)
+	assert.Equal(t, "2024-01-01", job.Since)
+
+	assert.Eventually(t, func() bool {
+		return cs.CancelJob(context.Background(), 10) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the job to be marked cancelled")
+	}
+}
+
+func TestCommitService_CancelBackfillWaitsForJob(t *testing.T) {
+	mockGitHubService := new(MockGitHubService)
+	mockRepoService := new(MockRepositoryService)
+	mockBackfillRepo := new(MockBackfillJobRepository)
+
+	repoID := int64(1)
+	fetching := make(chan struct{})
+	var stopped atomic.Bool
+
+	mockBackfillRepo.On("FindUnfinishedByRepositoryID", mock.Anything, repoID).Return((*domain.BackfillJob)(nil), nil)
+	mockBackfillRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.BackfillJob")).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.BackfillJob).ID = 10
+	}).Return(nil)
+	mockBackfillRepo.On("UpdateStatus", mock.Anything, int64(10), domain.BackfillStatusCancelled, "").Run(func(args mock.Arguments) {
+		time.Sleep(50 * time.Millisecond)
+		stopped.Store(true)
+	}).Return(nil)
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, repoID).Return("owner", "name", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "name", "owner").Return(&domain.Repository{ID: repoID, Owner: "owner", Name: "name"}, nil)
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "name", mock.Anything, repoID, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		errChan := args.Get(6).(chan<- error)
+		close(fetching)
+		<-ctx.Done()
+		errChan <- ctx.Err()
+	}).Return(nil)
+
+	cs := services.NewCommitService(mockGitHubService, mockRepoService, new(MockCommitRepository), mockBackfillRepo, make(chan int64), make(chan int64, 1), "2022-01-01", "")
+
+	_, err := cs.StartBackfill(context.Background(), repoID)
+	assert.NoError(t, err)
+	<-fetching
+
+	assert.NoError(t, cs.CancelBackfill(context.Background(), repoID))
+	assert.True(t, stopped.Load(), "expected the job to have stopped")
+}
+
+func TestCommitService_CancelJobNotRunning(t *testing.T) {
+	mockBackfillRepo := new(MockBackfillJobRepository)
+	mockBackfillRepo.On("FindByID", mock.Anything, int64(10)).Return(&domain.BackfillJob{ID: 10, Status: domain.BackfillStatusCompleted}, nil)
+	mockBackfillRepo.On("FindByID", mock.Anything, int64(11)).Return((*domain.BackfillJob)(nil), nil)
+
+	cs := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), new(MockCommitRepository), mockBackfillRepo, make(chan int64), make(chan int64), "", "")
+
+	assert.ErrorIs(t, cs.CancelJob(context.Background(), 10), services.ErrJobNotRunning)
+	assert.ErrorIs(t, cs.CancelJob(context.Background(), 11), services.ErrJobNotFound)
+}
+
+func TestCommitService_RetryJobRequiresFailedJob(t *testing.T) {
+	mockBackfillRepo := new(MockBackfillJobRepository)
+	mockBackfillRepo.On("FindByID", mock.Anything, int64(10)).Return(&domain.BackfillJob{ID: 10, RepositoryID: 1, Status: domain.BackfillStatusCompleted}, nil)
+	mockBackfillRepo.On("FindByID", mock.Anything, int64(11)).Return(&domain.BackfillJob{ID: 11, RepositoryID: 1, Status: domain.BackfillStatusFailed}, nil)
+	mockBackfillRepo.On("FindUnfinishedByRepositoryID", mock.Anything, int64(1)).Return(&domain.BackfillJob{ID: 12, RepositoryID: 1, Status: domain.BackfillStatusRunning}, nil)
+
+	cs := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), new(MockCommitRepository), mockBackfillRepo, make(chan int64), make(chan int64), "", "")
+
+	_, err := cs.RetryJob(context.Background(), 10)
+	assert.ErrorIs(t, err, services.ErrJobNotRetryable)
+
+	_, err = cs.RetryJob(context.Background(), 11)
+	assert.ErrorIs(t, err, services.ErrBackfillInProgress)
+	mockBackfillRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommitManager_CatchesUpBackfilledRepository(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	mockBackfillRepo := new(MockBackfillJobRepository)
+	commitChan := make(chan int64, 1)
+	monitoringChan := make(chan int64, 1)
+	service := services.NewCommitService(new(MockGitHubService), new(MockRepositoryService), mockCommitRepo, mockBackfillRepo, commitChan, monitoringChan, "2023-01-01", "")
+
+	mockBackfillRepo.On("FindUnfinishedByRepositoryID", mock.Anything, int64(1)).Return((*domain.BackfillJob)(nil), nil)
+	mockBackfillRepo.On("FindCompletedByRepositoryID", mock.Anything, int64(1)).Return(&domain.BackfillJob{ID: 3, RepositoryID: 1, Status: domain.BackfillStatusCompleted}, nil)
+
+	go service.CommitManager()
+	commitChan <- 1
+
+	select {
+	case id := <-monitoringChan:
+		assert.Equal(t, int64(1), id)
+	case <-time.After(time.Second):
+		t.Fatal("expected repoID in monitoringChan")
+	}
+	close(commitChan)
+
+	mockCommitRepo.AssertExpectations(t)
+	mockBackfillRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/olusolaa/github-monitor/config"
+)
+
+func writeRepositoriesFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "repositories.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRepositories(t *testing.T) {
+	path := writeRepositoriesFile(t, `
+repositories:
+  - owner: golang
+    name: go
+    start_date: 2024-08-01
+    end_date: 2024-08-03T00:00:00Z
+    branches: [master, release-branch.*]
+    schedule: 15m
+    labels: [language, google]
+  - owner: my-org
+`)
+
+	repositories, err := config.LoadRepositories(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []config.RepositoryConfig{
+		{
+			Owner:     "golang",
+			Name:      "go",
+			StartDate: "2024-08-01",
+			EndDate:   "2024-08-03T00:00:00Z",
+			Branches:  []string{"master", "release-branch.*"},
+			Schedule:  "15m",
+			Labels:    []string{"language", "google"},
+		},
+		{Owner: "my-org"},
+	}, repositories)
+}
+
+func TestLoadRepositories_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing owner":   "repositories:\n  - name: go\n",
+		"duplicate":       "repositories:\n  - owner: golang\n    name: go\n  - owner: Golang\n    name: Go\n",
+		"invalid date":    "repositories:\n  - owner: golang\n    name: go\n    start_date: yesterday\n",
+		"dates reversed":  "repositories:\n  - owner: golang\n    name: go\n    start_date: 2024-08-02\n    end_date: 2024-08-01\n",
+		"invalid cron":    "repositories:\n  - owner: golang\n    name: go\n    schedule: every day\n",
+		"unknown setting": "repositories:\n  - owner: golang\n    name: go\n    brnach: main\n",
+		"owner settings":  "repositories:\n  - owner: golang\n    branches: [main]\n",
+		"invalid branch":  "repositories:\n  - owner: golang\n    name: go\n    branches: [\"release/[\"]\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.LoadRepositories(writeRepositoriesFile(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
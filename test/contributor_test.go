@@ -0,0 +1,273 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	httpHandlers "github.com/olusolaa/github-monitor/internal/adapters/http"
+	"github.com/olusolaa/github-monitor/internal/adapters/postgresdb"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/errors"
+)
+
+type MockContributorRepository struct {
+	mock.Mock
+}
+
+func (m *MockContributorRepository) FindAll(ctx context.Context) ([]domain.Contributor, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Contributor), args.Error(1)
+}
+
+func (m *MockContributorRepository) FindByID(ctx context.Context, id int64) (*domain.Contributor, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Contributor), args.Error(1)
+}
+
+func (m *MockContributorRepository) Create(ctx context.Context, contributor *domain.Contributor) error {
+	args := m.Called(ctx, contributor)
+	return args.Error(0)
+}
+
+func (m *MockContributorRepository) Update(ctx context.Context, contributor *domain.Contributor) error {
+	args := m.Called(ctx, contributor)
+	return args.Error(0)
+}
+
+func (m *MockContributorRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockContributorRepository) Merge(ctx context.Context, id, mergedID int64) error {
+	args := m.Called(ctx, id, mergedID)
+	return args.Error(0)
+}
+
+func (m *MockContributorRepository) SaveAlias(ctx context.Context, alias *domain.ContributorAlias) error {
+	args := m.Called(ctx, alias)
+	return args.Error(0)
+}
+
+func (m *MockContributorRepository) DeleteAlias(ctx context.Context, contributorID, aliasID int64) (bool, error) {
+	args := m.Called(ctx, contributorID, aliasID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockContributorRepository) ImportMailmap(ctx context.Context, entries []domain.MailmapEntry) error {
+	args := m.Called(ctx, entries)
+	return args.Error(0)
+}
+
+func (m *MockContributorRepository) Seed(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestParseMailmap(t *testing.T) {
+	mailmap := `# Identities of the team
+Jane Doe <jane@example.com>
+<jane@example.com> <Jane@Home.example>
+Jane Doe <jane@example.com> <1234+jane@users.noreply.github.com>
+Jane Doe <jane@example.com> jdoe <jdoe@old.example> # before the rename
+
+`
+	entries, err := domain.ParseMailmap(strings.NewReader(mailmap))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.MailmapEntry{
+		{ProperName: "Jane Doe", CommitEmail: "jane@example.com"},
+		{ProperEmail: "jane@example.com", CommitEmail: "jane@home.example"},
+		{ProperName: "Jane Doe", ProperEmail: "jane@example.com", CommitEmail: "1234+jane@users.noreply.github.com"},
+		{ProperName: "Jane Doe", ProperEmail: "jane@example.com", CommitName: "jdoe", CommitEmail: "jdoe@old.example"},
+	}, entries)
+}
+
+func TestParseMailmapInvalid(t *testing.T) {
+	for _, line := range []string{"Jane Doe", "<jane@example.com>", "Jane <jane@example.com", "Jane <a@example.com> <b@example.com> trailing"} {
+		_, err := domain.ParseMailmap(strings.NewReader("# header\n" + line))
+		if assert.Error(t, err, line) {
+			assert.Contains(t, err.Error(), "line 2")
+		}
+	}
+}
+
+func TestContributorService_CreateContributor(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	contributor := &domain.Contributor{
+		Name:    " Jane Doe ",
+		Email:   "Jane@Example.com",
+		Aliases: []domain.ContributorAlias{{Email: "JANE@home.example"}, {Login: "Jane-Doe"}},
+	}
+	mockContributorRepo.On("Create", mock.Anything, contributor).Return(nil)
+
+	err := service.CreateContributor(context.Background(), contributor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", contributor.Name)
+	assert.Equal(t, "jane@example.com", contributor.Email)
+	assert.Equal(t, []domain.ContributorAlias{
+		{Email: "jane@home.example", Source: domain.ContributorSourceManual},
+		{Login: "jane-doe", Source: domain.ContributorSourceManual},
+	}, contributor.Aliases)
+	mockContributorRepo.AssertExpectations(t)
+}
+
+func TestContributorService_CreateContributorWithLogin(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	contributor := &domain.Contributor{Name: "Jane Doe", Login: "Jane-Doe"}
+	mockContributorRepo.On("Create", mock.Anything, contributor).Return(nil)
+
+	err := service.CreateContributor(context.Background(), contributor)
+
+	// The commits of the login resolve to the contributor through its login alias.
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ContributorAlias{
+		{Login: "jane-doe", Source: domain.ContributorSourceManual},
+	}, contributor.Aliases)
+	mockContributorRepo.AssertExpectations(t)
+}
+
+func TestContributorService_UpdateContributorReloadsAliases(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	mockContributorRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.Contributor{ID: 1, Name: "Jane", Login: "jane-old"}, nil).Once()
+	updated := &domain.Contributor{ID: 1, Name: "Jane", Login: "jane", Aliases: []domain.ContributorAlias{{ID: 5, ContributorID: 1, Login: "jane", Source: domain.ContributorSourceManual}}}
+	mockContributorRepo.On("FindByID", mock.Anything, int64(1)).Return(updated, nil).Once()
+	mockContributorRepo.On("Update", mock.Anything, mock.MatchedBy(func(c *domain.Contributor) bool {
+		return c.ID == 1 && c.Login == "jane"
+	})).Return(nil)
+
+	contributor := &domain.Contributor{ID: 1, Name: "Jane", Login: " Jane "}
+	err := service.UpdateContributor(context.Background(), contributor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, updated, contributor)
+	mockContributorRepo.AssertExpectations(t)
+}
+
+func TestContributorService_CreateContributorInvalid(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	for _, contributor := range []*domain.Contributor{
+		{Email: "jane@example.com"},
+		{Name: "Jane Doe", Aliases: []domain.ContributorAlias{{Name: "Jane"}}},
+	} {
+		err := service.CreateContributor(context.Background(), contributor)
+		assert.Error(t, err)
+	}
+	mockContributorRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestContributorService_AddAliasConflict(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	mockContributorRepo.On("FindByID", mock.Anything, int64(1)).Return(&domain.Contributor{ID: 1, Name: "Jane Doe"}, nil)
+	mockContributorRepo.On("SaveAlias", mock.Anything, mock.Anything).Return(fmt.Errorf("failed to save contributor alias: %w", postgresdb.ErrConflict))
+
+	err := service.AddAlias(context.Background(), &domain.ContributorAlias{ContributorID: 1, Login: "jane"})
+
+	var customErr *errors.CustomError
+	if assert.True(t, stderrors.As(err, &customErr)) {
+		assert.Equal(t, "CONTRIBUTOR_CONFLICT", customErr.Code)
+		assert.Equal(t, errors.Warning, customErr.Severity)
+	}
+}
+
+func TestContributorService_AddAliasUnknownContributor(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	mockContributorRepo.On("FindByID", mock.Anything, int64(9)).Return((*domain.Contributor)(nil), nil)
+
+	err := service.AddAlias(context.Background(), &domain.ContributorAlias{ContributorID: 9, Email: "jane@example.com"})
+
+	assert.Error(t, err)
+	mockContributorRepo.AssertNotCalled(t, "SaveAlias", mock.Anything, mock.Anything)
+}
+
+func TestContributorService_MergeContributors(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	_, err := service.MergeContributors(context.Background(), 1, 1)
+	assert.Error(t, err)
+
+	merged := &domain.Contributor{ID: 1, Name: "Jane Doe", Aliases: []domain.ContributorAlias{{ID: 3, ContributorID: 1, Login: "jane"}, {ID: 4, ContributorID: 1, Email: "jane@home.example"}}}
+	mockContributorRepo.On("FindByID", mock.Anything, int64(1)).Return(merged, nil)
+	mockContributorRepo.On("FindByID", mock.Anything, int64(2)).Return(&domain.Contributor{ID: 2, Name: "jane"}, nil)
+	mockContributorRepo.On("Merge", mock.Anything, int64(1), int64(2)).Return(nil)
+
+	contributor, err := service.MergeContributors(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, merged, contributor)
+	mockContributorRepo.AssertExpectations(t)
+}
+
+func TestContributorService_ImportMailmap(t *testing.T) {
+	mockContributorRepo := new(MockContributorRepository)
+	service := services.NewContributorService(mockContributorRepo)
+
+	mockContributorRepo.On("ImportMailmap", mock.Anything, []domain.MailmapEntry{
+		{ProperName: "Jane Doe", ProperEmail: "jane@example.com", CommitEmail: "jane@home.example"},
+	}).Return(nil)
+
+	imported, err := service.ImportMailmap(context.Background(), strings.NewReader("Jane Doe <jane@example.com> <jane@home.example>\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, imported)
+	mockContributorRepo.AssertExpectations(t)
+
+	_, err = service.ImportMailmap(context.Background(), strings.NewReader("Jane Doe\n"))
+	assert.Error(t, err)
+}
+
+func TestAdminRoutes_ImportMailmap(t *testing.T) {
+	os.Setenv("API_KEY", "admin-key")
+	defer os.Unsetenv("API_KEY")
+
+	mockContributorRepo := new(MockContributorRepository)
+	mockContributorRepo.On("ImportMailmap", mock.Anything, mock.Anything).Return(nil)
+	r := chi.NewRouter()
+	httpHandlers.RegisterAdminRoutes(r, nil, services.NewContributorService(mockContributorRepo))
+
+	body := "Jane Doe <jane@example.com> <jane@home.example>\nJane Doe <jane@example.com> <jane@old.example>\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/contributors/mailmap", strings.NewReader(body))
+	req.Header.Set("X-API-Key", "admin-key")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Entries int `json:"entries"`
+	}
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, 2, response.Entries)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/admin/contributors/mailmap", strings.NewReader("Jane Doe\n"))
+	req.Header.Set("X-API-Key", "admin-key")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
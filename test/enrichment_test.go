@@ -0,0 +1,127 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+)
+
+type MockCommitChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommitChangeRepository) FindPending(ctx context.Context, limit int, excludedIDs []int64) ([]domain.CommitRef, error) {
+	args := m.Called(ctx, limit, excludedIDs)
+	return args.Get(0).([]domain.CommitRef), args.Error(1)
+}
+
+func (m *MockCommitChangeRepository) Save(ctx context.Context, commitID int64, changes *domain.CommitChanges) error {
+	args := m.Called(ctx, commitID, changes)
+	return args.Error(0)
+}
+
+func (m *MockCommitChangeRepository) UpdateStatus(ctx context.Context, commitID int64, status string) error {
+	args := m.Called(ctx, commitID, status)
+	return args.Error(0)
+}
+
+func (m *MockCommitChangeRepository) RecordFailure(ctx context.Context, commitID int64, maxAttempts int) (bool, error) {
+	args := m.Called(ctx, commitID, maxAttempts)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCommitChangeRepository) MarkPending(ctx context.Context, repoID int64) (int, error) {
+	args := m.Called(ctx, repoID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCommitChangeRepository) FindFiles(ctx context.Context, owner, name, hash string) ([]domain.CommitFile, error) {
+	args := m.Called(ctx, owner, name, hash)
+	return args.Get(0).([]domain.CommitFile), args.Error(1)
+}
+
+func TestEnrichmentService_EnrichPending(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockChangeRepo := new(MockCommitChangeRepository)
+	service := services.NewEnrichmentService(mockGHService, new(MockRepositoryService), mockChangeRepo, 2)
+
+	changes := &domain.CommitChanges{Additions: 3, Deletions: 1, TotalChanges: 4, Files: []domain.CommitFile{{Path: "main.go", Status: "modified", Additions: 3, Deletions: 1}}}
+	mockChangeRepo.On("FindPending", mock.Anything, mock.Anything, []int64(nil)).Return([]domain.CommitRef{
+		{ID: 1, RepositoryID: 7, Owner: "owner", Name: "repo", Hash: "abc"},
+		{ID: 2, RepositoryID: 7, Owner: "owner", Name: "repo", Hash: "gone"},
+	}, nil).Once()
+	mockChangeRepo.On("FindPending", mock.Anything, mock.Anything, []int64(nil)).Return([]domain.CommitRef{}, nil).Once()
+	mockGHService.On("FetchCommitChanges", mock.Anything, "owner", "repo", "abc").Return(changes, nil)
+	mockGHService.On("FetchCommitChanges", mock.Anything, "owner", "repo", "gone").Return((*domain.CommitChanges)(nil), github.ErrNotFound)
+	mockChangeRepo.On("Save", mock.Anything, int64(1), changes).Return(nil)
+	mockChangeRepo.On("UpdateStatus", mock.Anything, int64(2), domain.EnrichmentStatusFailed).Return(nil)
+
+	enriched, err := service.EnrichPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, enriched)
+	mockChangeRepo.AssertExpectations(t)
+}
+
+func TestEnrichmentService_SkipsCommitsFailingToEnrich(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockChangeRepo := new(MockCommitChangeRepository)
+	service := services.NewEnrichmentService(mockGHService, new(MockRepositoryService), mockChangeRepo, 1)
+
+	changes := &domain.CommitChanges{Additions: 1, TotalChanges: 1}
+	mockChangeRepo.On("FindPending", mock.Anything, mock.Anything, []int64(nil)).Return([]domain.CommitRef{
+		{ID: 1, Owner: "owner", Name: "repo", Hash: "abc"},
+		{ID: 2, Owner: "owner", Name: "repo", Hash: "def"},
+	}, nil).Once()
+	mockChangeRepo.On("FindPending", mock.Anything, mock.Anything, []int64{1}).Return([]domain.CommitRef{}, nil).Once()
+	mockGHService.On("FetchCommitChanges", mock.Anything, "owner", "repo", "abc").Return((*domain.CommitChanges)(nil), errors.New("server error"))
+	mockGHService.On("FetchCommitChanges", mock.Anything, "owner", "repo", "def").Return(changes, nil)
+	mockChangeRepo.On("RecordFailure", mock.Anything, int64(1), 3).Return(false, nil)
+	mockChangeRepo.On("Save", mock.Anything, int64(2), changes).Return(nil)
+
+	enriched, err := service.EnrichPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, enriched)
+	mockChangeRepo.AssertExpectations(t)
+	mockChangeRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEnrichmentService_StopsWhenFailureCannotBeRecorded(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockChangeRepo := new(MockCommitChangeRepository)
+	service := services.NewEnrichmentService(mockGHService, new(MockRepositoryService), mockChangeRepo, 1)
+
+	mockChangeRepo.On("FindPending", mock.Anything, mock.Anything, []int64(nil)).Return([]domain.CommitRef{
+		{ID: 1, Owner: "owner", Name: "repo", Hash: "abc"},
+	}, nil)
+	mockGHService.On("FetchCommitChanges", mock.Anything, "owner", "repo", "abc").Return((*domain.CommitChanges)(nil), errors.New("server error"))
+	mockChangeRepo.On("RecordFailure", mock.Anything, int64(1), 3).Return(false, errors.New("connection refused"))
+
+	enriched, err := service.EnrichPending(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, enriched)
+	mockChangeRepo.AssertNumberOfCalls(t, "FindPending", 1)
+	mockChangeRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEnrichmentService_RequestEnrichmentUnknownRepository(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockChangeRepo := new(MockCommitChangeRepository)
+	service := services.NewEnrichmentService(new(MockGitHubService), mockRepoService, mockChangeRepo, 1)
+
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return((*domain.Repository)(nil), nil)
+
+	_, err := service.RequestEnrichment(context.Background(), "owner", "repo")
+
+	assert.Error(t, err)
+	mockChangeRepo.AssertNotCalled(t, "MarkPending", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+)
+
+func exportFixture() [][]domain.Commit {
+	additions := 3
+	date := time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC)
+	return [][]domain.Commit{
+		{{Hash: "abc", CommitDate: date, Message: "Fix, with \"quotes\"", AuthorName: "Jane", ParentSHAs: []string{"p1", "p2"}, Additions: &additions}},
+		{{Hash: "def", CommitDate: date.Add(-time.Hour), Message: "Initial commit", AuthorName: "John"}},
+	}
+}
+
+func TestExportService_ExportCommitsCSV(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewExportService(mockRepoRepo, mockCommitRepo)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1}, nil)
+	mockCommitRepo.On("StreamCommits", mock.Anything, "owner", "repo", domain.CommitFilter{}, mock.Anything).Return(exportFixture(), nil)
+
+	var out bytes.Buffer
+	exported, err := service.ExportCommits(context.Background(), "owner", "repo", domain.CommitFilter{}, domain.ExportFormatCSV, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, exported)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[0], "hash,commit_date,message,"))
+		assert.True(t, strings.HasPrefix(lines[1], `abc,2024-08-01T10:00:00Z,"Fix, with ""quotes""",Jane,`))
+		assert.Contains(t, lines[1], ",p1 p2,")
+		assert.Contains(t, lines[1], ",3,,,")
+	}
+}
+
+func TestExportService_ExportCommitsNDJSON(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewExportService(mockRepoRepo, mockCommitRepo)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1}, nil)
+	mockCommitRepo.On("StreamCommits", mock.Anything, "owner", "repo", domain.CommitFilter{}, mock.Anything).Return(exportFixture(), nil)
+
+	var out bytes.Buffer
+	_, err := service.ExportCommits(context.Background(), "owner", "repo", domain.CommitFilter{}, domain.ExportFormatNDJSON, &out)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		var commit domain.Commit
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &commit))
+		assert.Equal(t, "def", commit.Hash)
+	}
+}
+
+func TestExportService_RejectsUnknownFormat(t *testing.T) {
+	mockCommitRepo := new(MockCommitRepository)
+	service := services.NewExportService(new(MockRepositoryRepository), mockCommitRepo)
+
+	var out bytes.Buffer
+	_, err := service.ExportCommits(context.Background(), "owner", "repo", domain.CommitFilter{}, "parquet", &out)
+
+	assert.Error(t, err)
+	assert.Zero(t, out.Len())
+	mockCommitRepo.AssertNotCalled(t, "StreamCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,301 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/httpclient"
+)
+
+type memoryValidatorStore struct {
+	mu      sync.Mutex
+	entries map[string]domain.HTTPCacheEntry
+}
+
+func newMemoryValidatorStore() *memoryValidatorStore {
+	return &memoryValidatorStore{entries: make(map[string]domain.HTTPCacheEntry)}
+}
+
+func (s *memoryValidatorStore) Get(ctx context.Context, url string) (*domain.HTTPCacheEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[url]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (s *memoryValidatorStore) Upsert(ctx context.Context, entry *domain.HTTPCacheEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[entry.URL] = *entry
+	return nil
+}
+
+func TestClient_GetRepository_ConditionalRequest(t *testing.T) {
+	var seenIfNoneMatch []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenIfNoneMatch = append(seenIfNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name":"repo","forks_count":3}`))
+	}))
+	defer server.Close()
+
+	store := newMemoryValidatorStore()
+	client := github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), store)
+	ctx := github.WithConditionalRequests(context.Background())
+
+	repo, err := client.GetRepository(ctx, "owner", "repo")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, repo.ForksCount)
+
+	repo, err = client.GetRepository(ctx, "owner", "repo")
+	assert.ErrorIs(t, err, github.ErrNotModified)
+	assert.Nil(t, repo)
+
+	assert.Equal(t, []string{"", `"v1"`}, seenIfNoneMatch)
+}
+
+func TestClient_GetRepository_UnconditionalIgnoresCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name":"repo"}`))
+	}))
+	defer server.Close()
+
+	store := newMemoryValidatorStore()
+	store.Upsert(context.Background(), &domain.HTTPCacheEntry{URL: server.URL + "/repos/owner/repo", ETag: `"v1"`})
+	client := github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), store)
+
+	repo, err := client.GetRepository(context.Background(), "owner", "repo")
+	assert.NoError(t, err)
+	assert.Equal(t, "repo", repo.Name)
+}
+
+func TestClient_GetCommits_NotModifiedFirstPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"c1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"c1"`)
+		w.Write([]byte(`[{"sha":"abc"}]`))
+	}))
+	defer server.Close()
+
+	client := github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), newMemoryValidatorStore())
+	ctx := github.WithConditionalRequests(context.Background())
+
+	fetch := func() ([][]github.Commit, error) {
+		commitsChan := make(chan []github.Commit, 10)
+		errChan := make(chan error, 1)
+		client.GetCommits(ctx, "owner", "repo", github.CommitListOptions{}, commitsChan, errChan)
+		close(commitsChan)
+		var pages [][]github.Commit
+		for page := range commitsChan {
+			pages = append(pages, page)
+		}
+		return pages, <-errChan
+	}
+
+	pages, err := fetch()
+	assert.NoError(t, err)
+	assert.Len(t, pages, 1)
+
+	pages, err = fetch()
+	assert.NoError(t, err)
+	assert.Empty(t, pages)
+}
+
+func TestClient_ListRepositories_Organization(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/acme":
+			w.Write([]byte(`{"login":"acme","type":"Organization"}`))
+		case "/orgs/acme/repos":
+			assert.Equal(t, "all", r.URL.Query().Get("type"))
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("Link", `<`+server.URL+`/orgs/acme/repos?page=2>; rel="next"`)
+				w.Write([]byte(`[{"name":"api","language":"Go"},{"name":"old","archived":true}]`))
+				return
+			}
+			w.Write([]byte(`[{"name":"web","fork":true}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), nil)
+
+	repos, err := client.ListRepositories(context.Background(), "acme")
+
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 3) {
+		assert.Equal(t, "api", repos[0].Name)
+		assert.True(t, repos[1].Archived)
+		assert.True(t, repos[2].Fork)
+	}
+}
+
+func TestClient_CompareCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/compare/abc...main":
+			w.Write([]byte(`{"status":"diverged","ahead_by":2,"behind_by":1,"merge_base_commit":{"sha":"base"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), nil)
+
+	comparison, err := client.CompareCommits(context.Background(), "owner", "repo", "abc", "main")
+	assert.NoError(t, err)
+	assert.False(t, comparison.HeadContainsBase())
+	assert.Equal(t, "base", comparison.MergeBaseCommit.Sha)
+
+	_, err = client.CompareCommits(context.Background(), "owner", "repo", "gone", "main")
+	assert.ErrorIs(t, err, github.ErrNotFound)
+}
+
+func TestClient_GetCommit_FollowsFilePages(t *testing.T) {
+	const pages = 10
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/commits/abc" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "300", r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < pages {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/commits/abc?page=%d>; rel="next"`, server.URL, page+1))
+		}
+		files := make([]string, 300)
+		for i := range files {
+			files[i] = fmt.Sprintf(`{"filename": "file%d_%d.go", "status": "added"}`, page, i)
+		}
+		fmt.Fprintf(w, `{"sha": "abc", "stats": {"additions": 1, "deletions": 0, "total": 1}, "files": [%s]}`, strings.Join(files, ","))
+	}))
+	defer server.Close()
+
+	client := github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), nil)
+
+	commit, err := client.GetCommit(context.Background(), "owner", "repo", "abc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", commit.Sha)
+	assert.Len(t, commit.Files, github.MaxCommitFiles)
+	assert.Equal(t, "file1_0.go", commit.Files[0].Filename)
+	assert.Equal(t, "file10_299.go", commit.Files[len(commit.Files)-1].Filename)
+	assert.True(t, commit.FilesTruncated)
+}
+
+func TestGitHubService_FetchCommitsSeparatesAuthorAndCommitter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{
+			"sha": "abc",
+			"html_url": "https://github.com/owner/repo/commit/abc",
+			"commit": {
+				"author": {"name": "Jane Doe", "email": "jane@example.com", "date": "2024-08-01T10:00:00Z"},
+				"committer": {"name": "GitHub", "email": "noreply@github.com", "date": "2024-08-02T10:00:00Z"},
+				"message": "Squashed",
+				"tree": {"sha": "tree"},
+				"verification": {"verified": true, "reason": "valid"}
+			},
+			"author": {"login": "jane", "id": 42},
+			"committer": null,
+			"parents": [{"sha": "p1"}, {"sha": "p2"}]
+		}]`))
+	}))
+	defer server.Close()
+
+	gitHubService := services.NewGitHubService(github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), nil))
+	commitsChan := make(chan []domain.Commit, 1)
+	errChan := make(chan error, 1)
+
+	gitHubService.FetchCommits(context.Background(), "owner", "repo", github.CommitListOptions{}, 1, commitsChan, errChan)
+
+	assert.NoError(t, <-errChan)
+	commits := <-commitsChan
+	if assert.Len(t, commits, 1) {
+		commit := commits[0]
+		assert.Equal(t, "Jane Doe", commit.AuthorName)
+		assert.Equal(t, "jane", commit.AuthorLogin)
+		assert.Equal(t, int64(42), *commit.AuthorID)
+		assert.Equal(t, time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC), *commit.AuthorDate)
+		assert.Equal(t, "GitHub", commit.CommitterName)
+		assert.Nil(t, commit.CommitterID)
+		assert.Equal(t, time.Date(2024, 8, 2, 10, 0, 0, 0, time.UTC), commit.CommitDate)
+		assert.Equal(t, []string{"p1", "p2"}, []string(commit.ParentSHAs))
+		assert.Equal(t, "tree", commit.TreeSHA)
+		assert.True(t, commit.Verified)
+		assert.Equal(t, "https://github.com/owner/repo/commit/abc", commit.HTMLURL)
+	}
+}
+
+func TestGitHubService_FetchCommitChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits/abc":
+			w.Write([]byte(`{
+				"sha": "abc",
+				"commit": {
+					"author": {"name": "Jane", "email": "jane@example.com", "date": "2024-08-01T10:00:00Z"},
+					"committer": {"name": "GitHub", "email": "noreply@github.com", "date": "2024-08-02T10:00:00Z"}
+				},
+				"author": {"login": "jane", "id": 7},
+				"stats": {"additions": 12, "deletions": 3, "total": 15},
+				"files": [
+					{"filename": "cmd/main.go", "status": "modified", "additions": 2, "deletions": 3, "changes": 5},
+					{"filename": "pkg/new.go", "status": "renamed", "additions": 10, "deletions": 0, "changes": 10, "previous_filename": "pkg/old.go"}
+				]
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gitHubService := services.NewGitHubService(github.NewClient(server.URL, httpclient.NewClient(http.DefaultClient), nil))
+
+	changes, err := gitHubService.FetchCommitChanges(context.Background(), "owner", "repo", "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, changes.Additions)
+	assert.Equal(t, 3, changes.Deletions)
+	assert.Equal(t, 15, changes.TotalChanges)
+	assert.Equal(t, []domain.CommitFile{
+		{Path: "cmd/main.go", Status: "modified", Additions: 2, Deletions: 3},
+		{Path: "pkg/new.go", Status: "renamed", Additions: 10, PreviousFilename: "pkg/old.go"},
+	}, changes.Files)
+	assert.Equal(t, "Jane", changes.Metadata.AuthorName)
+	assert.Equal(t, "jane", changes.Metadata.AuthorLogin)
+	assert.Equal(t, "GitHub", changes.Metadata.CommitterName)
+	if assert.NotNil(t, changes.Metadata.AuthorDate) {
+		assert.Equal(t, time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC), changes.Metadata.AuthorDate.UTC())
+	}
+
+	_, err = gitHubService.FetchCommitChanges(context.Background(), "owner", "repo", "gone")
+	assert.ErrorIs(t, err, github.ErrNotFound)
+}
@@ -0,0 +1,202 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+)
+
+func TestMonitorService_SkipsPausedRepository(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockGitHubService := new(MockGitHubService)
+	monitorService := services.NewMonitorService(mockRepoService, nil, mockGitHubService, 3, time.Millisecond)
+
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, int64(1)).Return("owner", "repo", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1, MonitoringStatus: domain.RepositoryStatusPaused}, nil)
+
+	err := monitorService.MonitorRepository(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockGitHubService.AssertNotCalled(t, "FetchRepository", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMonitorService_PollRepositoryReportsRecentCommits(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockGitHubService := new(MockGitHubService)
+	mockCommitRepo := new(MockCommitRepository)
+	commitService := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	monitorService := services.NewMonitorService(mockRepoService, commitService, mockGitHubService, 1, time.Millisecond)
+
+	oldCommit := &domain.Commit{RepositoryID: 1, Hash: "old", CommitDate: time.Now().Add(-time.Hour)}
+	newCommits := []domain.Commit{{RepositoryID: 1, Hash: "new", CommitDate: time.Now()}}
+
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, int64(1)).Return("owner", "repo", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1, Owner: "owner", Name: "repo", MonitoringStatus: domain.RepositoryStatusActive}, nil)
+	mockGitHubService.On("FetchRepository", mock.Anything, "owner", "repo").Return((*domain.Repository)(nil), github.ErrNotModified)
+	mockCommitRepo.On("GetLatestCommitByRepositoryID", mock.Anything, int64(1)).Return(oldCommit, nil)
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "repo", mock.Anything, int64(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		commitsChan := args.Get(5).(chan<- []domain.Commit)
+		errChan := args.Get(6).(chan<- error)
+		go func() {
+			commitsChan <- newCommits
+			errChan <- nil
+		}()
+	})
+	mockCommitRepo.On("Save", mock.Anything, newCommits).Return(nil)
+	mockCommitRepo.On("CountCommitsSince", mock.Anything, int64(1), mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) >= 30*time.Minute && time.Since(since) < 31*time.Minute
+	})).Return(3, nil)
+
+	recentCommits, err := monitorService.PollRepository(context.Background(), 1, 30*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, recentCommits)
+	mockCommitRepo.AssertExpectations(t)
+}
+
+func TestMonitorService_PollRepositorySkipsPausedRepository(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	monitorService := services.NewMonitorService(mockRepoService, nil, new(MockGitHubService), 3, time.Millisecond)
+
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, int64(1)).Return("owner", "repo", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1, MonitoringStatus: domain.RepositoryStatusPaused}, nil)
+
+	recentCommits, err := monitorService.PollRepository(context.Background(), 1, time.Hour)
+
+	assert.ErrorIs(t, err, services.ErrRepositoryPaused)
+	assert.Zero(t, recentCommits)
+}
+
+func TestMonitorService_MonitorsEveryTrackedBranch(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockGitHubService := new(MockGitHubService)
+	mockCommitRepo := new(MockCommitRepository)
+	commitService := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	monitorService := services.NewMonitorService(mockRepoService, commitService, mockGitHubService, 1, time.Millisecond)
+
+	latest := &domain.Commit{RepositoryID: 1, Hash: "latest", CommitDate: time.Date(2024, 8, 10, 0, 0, 0, 0, time.UTC)}
+	latestOnMain := &domain.Commit{RepositoryID: 1, Hash: "main", CommitDate: time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)}
+
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, int64(1)).Return("owner", "repo", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1, Owner: "owner", Name: "repo", Branches: pq.StringArray{"main", "release/*"}}, nil)
+	mockGitHubService.On("ListBranches", mock.Anything, "owner", "repo").Return([]string{"dev", "main", "release/1.0"}, nil)
+	mockCommitRepo.On("GetLatestCommitByRepositoryID", mock.Anything, int64(1)).Return(latest, nil)
+	mockCommitRepo.On("GetLatestCommitByBranch", mock.Anything, int64(1), "main").Return(latestOnMain, nil)
+	mockCommitRepo.On("GetLatestCommitByBranch", mock.Anything, int64(1), "release/1.0").Return((*domain.Commit)(nil), nil)
+	mockGitHubService.On("GetBranchHead", mock.Anything, "owner", "repo", "main").Return("main", nil)
+	for _, opts := range []github.CommitListOptions{
+		{Since: "2024-08-05T00:00:00Z", Branch: "main"},
+		{Since: "2024-08-10T00:00:00Z", Branch: "release/1.0"},
+	} {
+		mockGitHubService.On("FetchCommits", mock.Anything, "owner", "repo", opts, int64(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			errChan := args.Get(6).(chan<- error)
+			go func() { errChan <- nil }()
+		}).Once()
+	}
+
+	err := monitorService.MonitorRepositoryCommits(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockGitHubService.AssertExpectations(t)
+	mockGitHubService.AssertNotCalled(t, "CompareCommits", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockCommitRepo.AssertExpectations(t)
+}
+
+func TestMonitorService_ResyncsRewrittenBranch(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockGitHubService := new(MockGitHubService)
+	mockCommitRepo := new(MockCommitRepository)
+	commitService := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	monitorService := services.NewMonitorService(mockRepoService, commitService, mockGitHubService, 1, time.Millisecond)
+
+	storedHead := &domain.Commit{RepositoryID: 1, Hash: "old-head", CommitDate: time.Date(2024, 8, 10, 0, 0, 0, 0, time.UTC)}
+	comparison := &github.Comparison{Status: github.ComparisonDiverged}
+	comparison.MergeBaseCommit.Sha = "base"
+	comparison.MergeBaseCommit.Commit.Committer.Date = time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	oldestOrphan := time.Date(2024, 8, 2, 0, 0, 0, 0, time.UTC)
+	refetched := []domain.Commit{{RepositoryID: 1, Hash: "new-head", Branch: "main", CommitDate: time.Date(2024, 8, 9, 0, 0, 0, 0, time.UTC)}}
+
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, int64(1)).Return("owner", "repo", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1, Owner: "owner", Name: "repo", DefaultBranch: "main"}, nil)
+	mockCommitRepo.On("GetLatestCommitByRepositoryID", mock.Anything, int64(1)).Return(storedHead, nil)
+	mockCommitRepo.On("GetLatestCommitByBranch", mock.Anything, int64(1), "main").Return(storedHead, nil)
+	mockGitHubService.On("CompareCommits", mock.Anything, "owner", "repo", "old-head", "new-head").Return(comparison, nil)
+	mockGitHubService.On("GetBranchHead", mock.Anything, "owner", "repo", "main").Return("new-head", nil)
+	mockCommitRepo.On("RecordHistoryRewrite", mock.Anything, &domain.HistoryRewrite{
+		RepositoryID: 1,
+		Branch:       "main",
+		BeforeSHA:    "old-head",
+		AfterSHA:     "new-head",
+		MergeBaseSHA: "base",
+	}, comparison.MergeBaseCommit.Commit.Committer.Date, []string{"new-head"}).Return(&oldestOrphan, nil)
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "repo", github.CommitListOptions{Since: "2024-08-01T00:00:00Z", Branch: "main"}, int64(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		commitsChan := args.Get(5).(chan<- []domain.Commit)
+		errChan := args.Get(6).(chan<- error)
+		go func() {
+			commitsChan <- refetched
+			errChan <- nil
+		}()
+	}).Once()
+	mockCommitRepo.On("Save", mock.Anything, refetched).Return(nil).Once()
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "repo", github.CommitListOptions{Since: "2024-08-10T00:00:00Z", Branch: "main"}, int64(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		errChan := args.Get(6).(chan<- error)
+		go func() { errChan <- nil }()
+	}).Once()
+
+	err := monitorService.MonitorRepositoryCommits(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockGitHubService.AssertExpectations(t)
+	mockCommitRepo.AssertExpectations(t)
+}
+
+func TestMonitorService_RecollectsBranchWithoutMergeBase(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockGitHubService := new(MockGitHubService)
+	mockCommitRepo := new(MockCommitRepository)
+	commitService := services.NewCommitService(mockGitHubService, mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "2024-01-01", "")
+	monitorService := services.NewMonitorService(mockRepoService, commitService, mockGitHubService, 1, time.Millisecond)
+
+	storedHead := &domain.Commit{RepositoryID: 1, Hash: "old-head", CommitDate: time.Date(2024, 8, 10, 0, 0, 0, 0, time.UTC)}
+	recollected := []domain.Commit{{RepositoryID: 1, Hash: "new-head", Branch: "main", CommitDate: time.Date(2024, 8, 9, 0, 0, 0, 0, time.UTC)}}
+
+	mockRepoService.On("GetOwnerAndRepoName", mock.Anything, int64(1)).Return("owner", "repo", nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 1, Owner: "owner", Name: "repo", DefaultBranch: "main", StartDate: "2024-07-01"}, nil)
+	mockCommitRepo.On("GetLatestCommitByRepositoryID", mock.Anything, int64(1)).Return(storedHead, nil)
+	mockCommitRepo.On("GetLatestCommitByBranch", mock.Anything, int64(1), "main").Return(storedHead, nil)
+	mockGitHubService.On("GetBranchHead", mock.Anything, "owner", "repo", "main").Return("new-head", nil)
+	mockGitHubService.On("CompareCommits", mock.Anything, "owner", "repo", "old-head", "new-head").Return((*github.Comparison)(nil), github.ErrNotFound)
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "repo", github.CommitListOptions{Since: "2024-07-01", Branch: "main"}, int64(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		commitsChan := args.Get(5).(chan<- []domain.Commit)
+		errChan := args.Get(6).(chan<- error)
+		go func() {
+			commitsChan <- recollected
+			errChan <- nil
+		}()
+	}).Once()
+	mockCommitRepo.On("Save", mock.Anything, recollected).Return(nil).Once()
+	mockCommitRepo.On("RecordHistoryRewrite", mock.Anything, &domain.HistoryRewrite{
+		RepositoryID: 1,
+		Branch:       "main",
+		BeforeSHA:    "old-head",
+		AfterSHA:     "new-head",
+	}, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), []string{"new-head"}).Return((*time.Time)(nil), nil)
+	mockGitHubService.On("FetchCommits", mock.Anything, "owner", "repo", github.CommitListOptions{Since: "2024-08-10T00:00:00Z", Branch: "main"}, int64(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		errChan := args.Get(6).(chan<- error)
+		go func() { errChan <- nil }()
+	}).Once()
+
+	err := monitorService.MonitorRepositoryCommits(context.Background(), 1)
+
+	assert.NoError(t, err)
+	mockGitHubService.AssertExpectations(t)
+	mockCommitRepo.AssertExpectations(t)
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+)
+
+type MockOwnerRepository struct {
+	mock.Mock
+}
+
+func (m *MockOwnerRepository) Upsert(ctx context.Context, owner *domain.MonitoredOwner) error {
+	args := m.Called(ctx, owner)
+	return args.Error(0)
+}
+
+func (m *MockOwnerRepository) FindAll(ctx context.Context) ([]domain.MonitoredOwner, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.MonitoredOwner), args.Error(1)
+}
+
+func (m *MockOwnerRepository) Delete(ctx context.Context, owner string) error {
+	args := m.Called(ctx, owner)
+	return args.Error(0)
+}
+
+func TestRepositoryFilter_Matches(t *testing.T) {
+	filter := domain.RepositoryFilter{Include: []string{"service-*"}, Exclude: []string{"service-legacy"}, Languages: []string{"go"}}
+
+	assert.True(t, filter.Matches("service-api", "Go", false, false))
+	assert.False(t, filter.Matches("service-legacy", "Go", false, false))
+	assert.False(t, filter.Matches("website", "Go", false, false))
+	assert.False(t, filter.Matches("service-web", "TypeScript", false, false))
+	assert.False(t, filter.Matches("service-api", "Go", true, false))
+	assert.False(t, filter.Matches("service-api", "Go", false, true))
+
+	assert.True(t, domain.RepositoryFilter{IncludeArchived: true, IncludeForks: true}.Matches("anything", "", true, true))
+	assert.Error(t, domain.RepositoryFilter{Include: []string{"[a-"}}.Validate())
+}
+
+func TestMonitorOwner_AddsNewRepositories(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockRepoService := new(MockRepositoryService)
+	mockOwnerRepo := new(MockOwnerRepository)
+	service := services.NewOwnerService(mockGHService, mockRepoService, mockOwnerRepo)
+
+	filter := domain.RepositoryFilter{Include: []string{"service-*"}}
+	added := make(chan string, 2)
+
+	mockGHService.On("ListRepositories", mock.Anything, "acme", filter).Return([]domain.Repository{{Owner: "acme", Name: "service-api"}, {Owner: "acme", Name: "service-web"}}, nil)
+	mockRepoService.On("GetRepository", mock.Anything, "service-api", "acme").Return(&domain.Repository{ID: 1}, nil)
+	mockRepoService.On("GetRepository", mock.Anything, "service-web", "acme").Return((*domain.Repository)(nil), nil)
+	mockOwnerRepo.On("Upsert", mock.Anything, &domain.MonitoredOwner{Owner: "acme", RepositoryFilter: filter}).Return(nil)
+	mockRepoService.On("AddRepository", "acme", mock.Anything).Run(func(args mock.Arguments) {
+		added <- args.String(1)
+	}).Return(nil)
+
+	names, err := service.MonitorOwner(context.Background(), "acme", filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"service-web"}, names)
+	select {
+	case name := <-added:
+		assert.Equal(t, "service-web", name)
+	case <-time.After(time.Second):
+		t.Fatal("expected service-web to be added")
+	}
+	mockOwnerRepo.AssertExpectations(t)
+}
+
+func TestMonitorOwner_InvalidFilter(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	service := services.NewOwnerService(mockGHService, new(MockRepositoryService), new(MockOwnerRepository))
+
+	_, err := service.MonitorOwner(context.Background(), "acme", domain.RepositoryFilter{Exclude: []string{"[a-"}})
+
+	assert.Error(t, err)
+	mockGHService.AssertNotCalled(t, "ListRepositories", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReconcileOwners_StopsRemovedOwners(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockRepoService := new(MockRepositoryService)
+	mockOwnerRepo := new(MockOwnerRepository)
+	service := services.NewOwnerService(mockGHService, mockRepoService, mockOwnerRepo)
+
+	mockGHService.On("ListRepositories", mock.Anything, "acme", domain.RepositoryFilter{}).Return([]domain.Repository{}, nil)
+	mockOwnerRepo.On("Upsert", mock.Anything, &domain.MonitoredOwner{Owner: "acme", Managed: true}).Return(nil)
+	mockOwnerRepo.On("FindAll", mock.Anything).Return([]domain.MonitoredOwner{
+		{Owner: "acme", Managed: true},
+		{Owner: "globex", Managed: true},
+		{Owner: "initech"},
+	}, nil)
+	mockOwnerRepo.On("Delete", mock.Anything, "globex").Return(nil)
+	mockRepoService.On("StopOwnerRepositories", mock.Anything, "globex").Return(3, nil)
+
+	err := service.Reconcile(context.Background(), []string{"acme"})
+
+	assert.NoError(t, err)
+	mockOwnerRepo.AssertExpectations(t)
+	mockRepoService.AssertExpectations(t)
+	mockOwnerRepo.AssertNotCalled(t, "Delete", mock.Anything, "initech")
+}
+
+func TestReconcileOwners_KeepsFilterOfOwnerDeclaredInOtherCase(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockRepoService := new(MockRepositoryService)
+	mockOwnerRepo := new(MockOwnerRepository)
+	service := services.NewOwnerService(mockGHService, mockRepoService, mockOwnerRepo)
+
+	filter := domain.RepositoryFilter{Include: pq.StringArray{"service-*"}}
+	mockOwnerRepo.On("FindAll", mock.Anything).Return([]domain.MonitoredOwner{{Owner: "acme", RepositoryFilter: filter, Managed: true}}, nil)
+	mockGHService.On("ListRepositories", mock.Anything, "acme", filter).Return([]domain.Repository{}, nil)
+	mockOwnerRepo.On("Upsert", mock.Anything, &domain.MonitoredOwner{Owner: "acme", RepositoryFilter: filter, Managed: true}).Return(nil)
+
+	err := service.Reconcile(context.Background(), []string{"ACME"})
+
+	assert.NoError(t, err)
+	mockOwnerRepo.AssertExpectations(t)
+	mockOwnerRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockRepoService.AssertNotCalled(t, "StopOwnerRepositories", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/olusolaa/github-monitor/pkg/pagination"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := pagination.Cursor{Time: time.Date(2024, 8, 1, 10, 0, 0, 123000, time.UTC), ID: 42, Backward: true}
+
+	decoded, err := pagination.DecodeCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.True(t, cursor.Time.Equal(decoded.Time))
+	assert.Equal(t, int64(42), decoded.ID)
+	assert.True(t, decoded.Backward)
+
+	_, err = pagination.DecodeCursor("not a cursor")
+	assert.Error(t, err)
+}
+
+func TestParseCursorParams(t *testing.T) {
+	params, err := pagination.ParseCursorParams(url.Values{"page": {"2"}})
+	assert.NoError(t, err)
+	assert.Nil(t, params)
+
+	params, err = pagination.ParseCursorParams(url.Values{"cursor": {""}, "page_size": {"50"}, "include_total": {"true"}})
+	assert.NoError(t, err)
+	assert.Nil(t, params.Cursor)
+	assert.Equal(t, 50, params.PageSize)
+	assert.True(t, params.IncludeTotal)
+}
+
+func TestKeysetPage_Backward(t *testing.T) {
+	key := func(id int) pagination.Cursor { return pagination.Cursor{ID: int64(id)} }
+	params := &pagination.CursorParams{Cursor: &pagination.Cursor{ID: 7, Backward: true}, PageSize: 2}
+
+	// Scanning backwards from 7 yields 6, 5 and one more row, so there is a page before 5.
+	page, cursors := pagination.KeysetPage([]int{6, 5, 4}, params, key)
+
+	assert.Equal(t, []int{5, 6}, page)
+	prev, _ := pagination.DecodeCursor(cursors.Prev)
+	assert.Equal(t, &pagination.Cursor{ID: 5, Backward: true}, prev)
+	next, _ := pagination.DecodeCursor(cursors.Next)
+	assert.Equal(t, &pagination.Cursor{ID: 6}, next)
+}
+
+func TestKeysetPage_LastPage(t *testing.T) {
+	key := func(id int) pagination.Cursor { return pagination.Cursor{ID: int64(id)} }
+	params := &pagination.CursorParams{Cursor: &pagination.Cursor{ID: 3}, PageSize: 2}
+
+	page, cursors := pagination.KeysetPage([]int{2, 1}, params, key)
+
+	assert.Equal(t, []int{2, 1}, page)
+	assert.Empty(t, cursors.Next)
+	assert.NotEmpty(t, cursors.Prev)
+}
+
+func TestLinks(t *testing.T) {
+	u, _ := url.Parse("/api/repos/owner/repo/commits?author=jane&cursor=&page_size=2")
+
+	links := pagination.CursorLinks(u, &pagination.Cursors{Next: "abc"})
+	assert.Equal(t, `</api/repos/owner/repo/commits?author=jane&cursor=abc&page_size=2>; rel="next"`, links)
+
+	u, _ = url.Parse("/api/repos/owner/repo/commits?page=2")
+	links = pagination.PageLinks(u, pagination.NewPagination(2, 10, 25))
+	assert.Equal(t, `</api/repos/owner/repo/commits?page=1&page_size=10>; rel="first", `+
+		`</api/repos/owner/repo/commits?page=1&page_size=10>; rel="prev", `+
+		`</api/repos/owner/repo/commits?page=3&page_size=10>; rel="next", `+
+		`</api/repos/owner/repo/commits?page=3&page_size=10>; rel="last"`, links)
+}
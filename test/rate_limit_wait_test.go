@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	"github.com/olusolaa/github-monitor/pkg/httpclient"
+)
+
+// secondaryLimitedServer serves two pages of commits and answers the first request for page 2
+// with a secondary rate limit response.
+func secondaryLimitedServer(retryAfter string) (*httptest.Server, *int32) {
+	var page2Calls int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/commits?page=2>; rel="next"`, server.URL))
+			w.Write([]byte(`[{"sha":"a"}]`))
+		case "2":
+			if atomic.AddInt32(&page2Calls, 1) == 1 {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+				return
+			}
+			w.Write([]byte(`[{"sha":"b"}]`))
+		}
+	}))
+	return server, &page2Calls
+}
+
+func fetchCommitPages(ctx context.Context, client *github.Client) ([]string, error) {
+	commitsChan := make(chan []github.Commit, 10)
+	errChan := make(chan error, 1)
+	client.GetCommits(ctx, "owner", "repo", github.CommitListOptions{}, commitsChan, errChan)
+	close(commitsChan)
+
+	var shas []string
+	for page := range commitsChan {
+		for _, commit := range page {
+			shas = append(shas, commit.Sha)
+		}
+	}
+	return shas, <-errChan
+}
+
+func newRateLimitedClient(baseURL string) *github.Client {
+	rateLimiter := github.NewGitHubRateLimiter()
+	return github.NewClient(baseURL, httpclient.NewClient(http.DefaultClient, rateLimiter.RateLimitMiddleware), nil)
+}
+
+func TestFetchAllPages_FailsFastByDefault(t *testing.T) {
+	server, _ := secondaryLimitedServer("0")
+	defer server.Close()
+
+	shas, err := fetchCommitPages(context.Background(), newRateLimitedClient(server.URL))
+
+	var rateLimitErr *github.RateLimitExceededError
+	assert.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, []string{"a"}, shas)
+}
+
+func TestFetchAllPages_WaitsAndResumesFromSamePage(t *testing.T) {
+	server, page2Calls := secondaryLimitedServer("0")
+	defer server.Close()
+
+	ctx := github.WithRateLimitWait(context.Background())
+	shas, err := fetchCommitPages(ctx, newRateLimitedClient(server.URL))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, shas)
+	assert.Equal(t, int32(2), atomic.LoadInt32(page2Calls))
+}
+
+func TestFetchAllPages_WaitRespectsCancellation(t *testing.T) {
+	server, _ := secondaryLimitedServer("3600")
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(github.WithRateLimitWait(context.Background()), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := fetchCommitPages(ctx, newRateLimitedClient(server.URL))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestGetCommit_WaitsOutRateLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+			return
+		}
+		w.Write([]byte(`{"sha":"abc","stats":{"additions":1,"deletions":0,"total":1}}`))
+	}))
+	defer server.Close()
+
+	commit, err := newRateLimitedClient(server.URL).GetCommit(github.WithRateLimitWait(context.Background()), "owner", "repo", "abc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", commit.Sha)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"github.com/lib/pq"
 	"github.com/olusolaa/github-monitor/internal/core/domain"
 	"github.com/olusolaa/github-monitor/internal/core/services"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +20,11 @@ func (m *MockRepositoryRepository) FindByNameAndOwner(ctx context.Context, name,
 	return args.Get(0).(*domain.Repository), args.Error(1)
 }
 
+func (m *MockRepositoryRepository) FindMonitored(ctx context.Context) ([]domain.Repository, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Repository), args.Error(1)
+}
+
 func (m *MockRepositoryRepository) Upsert(ctx context.Context, repository *domain.Repository) error {
 	args := m.Called(ctx, repository)
 	return args.Error(0)
@@ -34,6 +40,46 @@ func (m *MockRepositoryRepository) Update(ctx context.Context, repo *domain.Repo
 	return args.Error(0)
 }
 
+func (m *MockRepositoryRepository) UpdateMonitoringStatus(ctx context.Context, repoID int64, status string) error {
+	args := m.Called(ctx, repoID, status)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryRepository) UpdateSchedule(ctx context.Context, repoID int64, schedule string) error {
+	args := m.Called(ctx, repoID, schedule)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryRepository) UpdatePollState(ctx context.Context, repoID int64, intervalSeconds, emptyPolls int) error {
+	args := m.Called(ctx, repoID, intervalSeconds, emptyPolls)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryRepository) UpdateSettings(ctx context.Context, repository *domain.Repository) error {
+	args := m.Called(ctx, repository)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryRepository) UpdateBranches(ctx context.Context, repoID int64, branches []string) error {
+	args := m.Called(ctx, repoID, branches)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryRepository) UpdateName(ctx context.Context, repoID int64, name string) error {
+	args := m.Called(ctx, repoID, name)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryRepository) FindManaged(ctx context.Context) ([]domain.Repository, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Repository), args.Error(1)
+}
+
+func (m *MockRepositoryRepository) Delete(ctx context.Context, repoID int64) error {
+	args := m.Called(ctx, repoID)
+	return args.Error(0)
+}
+
 func (m *MockRepositoryService) RepositoryManager(commitChan chan int64) {
 	m.Called(commitChan)
 }
@@ -43,7 +89,7 @@ func TestRepositoryManager(t *testing.T) {
 	mockRepoRepo := new(MockRepositoryRepository)
 	commitChan := make(chan int64, 1)
 	repoChan := make(chan services.RepoRequest, 1)
-	service := services.NewRepositoryService(mockGHService, mockRepoRepo, repoChan, commitChan)
+	service := services.NewRepositoryService(mockGHService, mockRepoRepo, repoChan, commitChan, nil, nil)
 
 	repo := &domain.Repository{ID: 1, Name: "testRepo", Owner: "testOwner"}
 	mockGHService.On("FetchRepository", mock.Anything, "testOwner", "testRepo").Return(repo, nil).Once()
@@ -63,3 +109,172 @@ func TestRepositoryManager(t *testing.T) {
 	mockGHService.AssertExpectations(t)
 	mockRepoRepo.AssertExpectations(t)
 }
+
+func TestFetchRepository_ReturnsStoredRepository(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockRepoRepo := new(MockRepositoryRepository)
+	service := services.NewRepositoryService(mockGHService, mockRepoRepo, make(chan services.RepoRequest), nil, nil, nil)
+
+	repo := &domain.Repository{ID: 7, Name: "Go", Owner: "Golang", MonitoringStatus: domain.RepositoryStatusActive}
+	mockGHService.On("FetchRepository", mock.Anything, "golang", "go").Return(repo, nil)
+	mockRepoRepo.On("Upsert", mock.Anything, repo).Return(nil)
+
+	stored, err := service.FetchRepository(context.Background(), "golang", "go", nil)
+
+	assert.NoError(t, err)
+	assert.Same(t, repo, stored)
+	mockRepoRepo.AssertExpectations(t)
+}
+
+func TestRemoveRepository_KeepsCommits(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	removalChan := make(chan int64, 1)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, removalChan, nil)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "testRepo", "testOwner").Return(&domain.Repository{ID: 1, Name: "testRepo", Owner: "testOwner"}, nil)
+	mockRepoRepo.On("UpdateMonitoringStatus", mock.Anything, int64(1), domain.RepositoryStatusStopped).Return(nil)
+
+	err := service.RemoveRepository(context.Background(), "testOwner", "testRepo", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), <-removalChan)
+	mockRepoRepo.AssertExpectations(t)
+	mockRepoRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestRemoveRepository_Purge(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	removalChan := make(chan int64, 1)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, removalChan, nil)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "testRepo", "testOwner").Return(&domain.Repository{ID: 1, Name: "testRepo", Owner: "testOwner"}, nil)
+	mockRepoRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
+
+	err := service.RemoveRepository(context.Background(), "testOwner", "testRepo", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), <-removalChan)
+	mockRepoRepo.AssertExpectations(t)
+}
+
+func TestRemoveRepository_NotFound(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	removalChan := make(chan int64, 1)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, removalChan, nil)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "testRepo", "testOwner").Return((*domain.Repository)(nil), nil)
+
+	err := service.RemoveRepository(context.Background(), "testOwner", "testRepo", false)
+
+	assert.Error(t, err)
+	assert.Empty(t, removalChan)
+}
+
+func TestPauseAndResumeRepository(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, nil, nil)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "testRepo", "testOwner").Return(&domain.Repository{ID: 1, MonitoringStatus: domain.RepositoryStatusActive}, nil)
+	mockRepoRepo.On("UpdateMonitoringStatus", mock.Anything, int64(1), domain.RepositoryStatusPaused).Return(nil).Once()
+	mockRepoRepo.On("UpdateMonitoringStatus", mock.Anything, int64(1), domain.RepositoryStatusActive).Return(nil).Once()
+
+	assert.NoError(t, service.PauseRepository(context.Background(), "testOwner", "testRepo"))
+	assert.NoError(t, service.ResumeRepository(context.Background(), "testOwner", "testRepo"))
+	mockRepoRepo.AssertExpectations(t)
+}
+
+func TestPauseRepository_Stopped(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, nil, nil)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "testRepo", "testOwner").Return(&domain.Repository{ID: 1, MonitoringStatus: domain.RepositoryStatusStopped}, nil)
+
+	assert.Error(t, service.PauseRepository(context.Background(), "testOwner", "testRepo"))
+	mockRepoRepo.AssertNotCalled(t, "UpdateMonitoringStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetSchedule(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	rescheduleChan := make(chan int64, 1)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, nil, rescheduleChan)
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "testRepo", "testOwner").Return(&domain.Repository{ID: 1}, nil)
+	mockRepoRepo.On("UpdateSchedule", mock.Anything, int64(1), "*/5 * * * *").Return(nil)
+
+	err := service.SetSchedule(context.Background(), "testOwner", "testRepo", "*/5 * * * *")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), <-rescheduleChan)
+	mockRepoRepo.AssertExpectations(t)
+}
+
+func TestSetSchedule_Invalid(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, nil, nil)
+
+	err := service.SetSchedule(context.Background(), "testOwner", "testRepo", "every now and then")
+
+	assert.Error(t, err)
+	mockRepoRepo.AssertNotCalled(t, "UpdateSchedule", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestoreMonitoring(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	commitChan := make(chan int64, 2)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, nil, nil)
+
+	mockRepoRepo.On("FindMonitored", mock.Anything).Return([]domain.Repository{{ID: 1}, {ID: 2, MonitoringStatus: domain.RepositoryStatusPaused}}, nil)
+
+	err := service.RestoreMonitoring(context.Background(), commitChan)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), <-commitChan)
+	assert.Equal(t, int64(2), <-commitChan)
+	mockRepoRepo.AssertExpectations(t)
+}
+
+func TestReconcile(t *testing.T) {
+	mockGHService := new(MockGitHubService)
+	mockRepoRepo := new(MockRepositoryRepository)
+	service := services.NewRepositoryService(mockGHService, mockRepoRepo, make(chan services.RepoRequest), nil, nil, nil)
+
+	added := &domain.Repository{ID: 1, Owner: "golang", Name: "go", MonitoringStatus: domain.RepositoryStatusActive}
+	removed := domain.Repository{ID: 2, Owner: "golang", Name: "tools", MonitoringStatus: domain.RepositoryStatusActive, Managed: true}
+
+	mockRepoRepo.On("FindByNameAndOwner", mock.Anything, "go", "golang").Return((*domain.Repository)(nil), nil)
+	mockGHService.On("FetchRepository", mock.Anything, "golang", "go").Return(added, nil)
+	mockRepoRepo.On("Upsert", mock.Anything, added).Return(nil)
+	mockRepoRepo.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(repository *domain.Repository) bool {
+		return repository.ID == 1 && repository.Managed && len(repository.Branches) == 1 && repository.Branches[0] == "main" && repository.StartDate == "2024-08-01"
+	})).Return(nil).Once()
+	mockRepoRepo.On("FindManaged", mock.Anything).Return([]domain.Repository{{ID: 1, Owner: "golang", Name: "go", Managed: true}, removed}, nil)
+	mockRepoRepo.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(repository *domain.Repository) bool {
+		return repository.ID == 2 && !repository.Managed
+	})).Return(nil).Once()
+	mockRepoRepo.On("UpdateMonitoringStatus", mock.Anything, int64(2), domain.RepositoryStatusStopped).Return(nil)
+
+	err := service.Reconcile(context.Background(), []domain.Repository{{Owner: "golang", Name: "go", Branches: pq.StringArray{"main"}, StartDate: "2024-08-01"}})
+
+	assert.NoError(t, err)
+	mockGHService.AssertExpectations(t)
+	mockRepoRepo.AssertExpectations(t)
+}
+
+func TestStopOwnerRepositories_KeepsDeclaredRepositories(t *testing.T) {
+	mockRepoRepo := new(MockRepositoryRepository)
+	service := services.NewRepositoryService(new(MockGitHubService), mockRepoRepo, make(chan services.RepoRequest), nil, nil, nil)
+
+	mockRepoRepo.On("FindMonitored", mock.Anything).Return([]domain.Repository{
+		{ID: 1, Owner: "acme", Name: "api"},
+		{ID: 2, Owner: "acme", Name: "web", Managed: true},
+		{ID: 3, Owner: "globex", Name: "api"},
+	}, nil)
+	mockRepoRepo.On("UpdateMonitoringStatus", mock.Anything, int64(1), domain.RepositoryStatusStopped).Return(nil)
+
+	stopped, err := service.StopOwnerRepositories(context.Background(), "acme")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stopped)
+	mockRepoRepo.AssertExpectations(t)
+	mockRepoRepo.AssertNumberOfCalls(t, "UpdateMonitoringStatus", 1)
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/olusolaa/github-monitor/pkg/utils"
+)
+
+func TestParseSchedule(t *testing.T) {
+	interval, cronExpr, err := utils.ParseSchedule("5m")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, interval)
+	assert.Empty(t, cronExpr)
+
+	interval, cronExpr, err = utils.ParseSchedule("0 3 * * *")
+	assert.NoError(t, err)
+	assert.Zero(t, interval)
+	assert.Equal(t, "0 3 * * *", cronExpr)
+
+	_, _, err = utils.ParseSchedule("-1h")
+	assert.Error(t, err)
+
+	_, _, err = utils.ParseSchedule("daily")
+	assert.Error(t, err)
+}
+
+func TestNextPollInterval(t *testing.T) {
+	min, max := time.Minute, time.Hour
+
+	interval, empty := utils.NextPollInterval(20*time.Minute, 2, 1, min, max)
+	assert.Equal(t, 10*time.Minute, interval)
+	assert.Zero(t, empty)
+
+	interval, empty = utils.NextPollInterval(20*time.Minute, 0, 4, min, max)
+	assert.Equal(t, 5*time.Minute, interval)
+	assert.Zero(t, empty)
+
+	interval, empty = utils.NextPollInterval(20*time.Minute, 0, 0, min, max)
+	assert.Equal(t, 20*time.Minute, interval)
+	assert.Equal(t, 1, empty)
+
+	interval, empty = utils.NextPollInterval(20*time.Minute, utils.EmptyPollsBeforeBackoff-1, 0, min, max)
+	assert.Equal(t, 40*time.Minute, interval)
+	assert.Zero(t, empty)
+
+	interval, _ = utils.NextPollInterval(10*time.Minute, 0, 50, min, max)
+	assert.Equal(t, min, interval)
+
+	interval, _ = utils.NextPollInterval(40*time.Minute, utils.EmptyPollsBeforeBackoff-1, 0, min, max)
+	assert.Equal(t, max, interval)
+}
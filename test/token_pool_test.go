@@ -0,0 +1,128 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/olusolaa/github-monitor/internal/adapters/github"
+	httpHandlers "github.com/olusolaa/github-monitor/internal/adapters/http"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+	"github.com/olusolaa/github-monitor/pkg/httpclient"
+)
+
+// rateLimitedServer hands out a fixed budget per token and rejects requests once it is spent.
+func rateLimitedServer(budgets map[string]int) *httptest.Server {
+	var mu sync.Mutex
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		token := r.Header.Get("Authorization")[len("Bearer "):]
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		if budgets[token] == 0 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		budgets[token]--
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(budgets[token]))
+		w.Write([]byte(token))
+	}))
+}
+
+func doGet(t *testing.T, client *httpclient.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return client.Do(req)
+}
+
+func TestTokenPool_RotatesToTokenWithBudget(t *testing.T) {
+	server := rateLimitedServer(map[string]int{"token-aaaa-1111": 1, "token-bbbb-2222": 5})
+	defer server.Close()
+
+	pool, err := github.NewTokenPool([]string{"token-aaaa-1111", "token-bbbb-2222"})
+	require.NoError(t, err)
+	client := httpclient.NewClient(http.DefaultClient, pool.Middleware)
+
+	for i := 0; i < 6; i++ {
+		resp, err := doGet(t, client, server.URL)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+	}
+
+	resp, err := doGet(t, client, server.URL)
+	assert.Nil(t, resp)
+	var rateLimitErr *github.RateLimitExceededError
+	assert.ErrorAs(t, err, &rateLimitErr)
+
+	for _, status := range pool.Status() {
+		assert.True(t, status.Exhausted)
+		assert.Equal(t, 0, status.Remaining)
+		assert.Equal(t, 5000, status.Limit)
+	}
+}
+
+func TestTokenPool_StatusMasksTokens(t *testing.T) {
+	pool, err := github.NewTokenPool([]string{"ghp_secretvalue1234", ""})
+	require.NoError(t, err)
+
+	status := pool.Status()
+
+	require.Len(t, status, 1)
+	assert.Equal(t, "***************1234", status[0].Token)
+	assert.Equal(t, -1, status[0].Remaining)
+	assert.False(t, status[0].Exhausted)
+}
+
+func TestTokenPool_RequiresToken(t *testing.T) {
+	for _, tokens := range [][]string{nil, {""}, {" ", "\t"}} {
+		_, err := github.NewTokenPool(tokens)
+		assert.ErrorIs(t, err, github.ErrNoTokens)
+	}
+}
+
+func TestAdminRoutes_RateLimitsRequireAPIKey(t *testing.T) {
+	os.Setenv("API_KEY", "admin-key")
+	defer os.Unsetenv("API_KEY")
+
+	pool, err := github.NewTokenPool([]string{"ghp_secretvalue1234"})
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	httpHandlers.RegisterAdminRoutes(r, pool, services.NewContributorService(new(MockContributorRepository)))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/admin/rate-limits", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/rate-limits", nil)
+	req.Header.Set("X-API-Key", "admin-key")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var statuses []github.TokenStatus
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&statuses))
+	assert.Len(t, statuses, 1)
+}
+
+func TestAdminRoutes_WithoutTokenPool(t *testing.T) {
+	r := chi.NewRouter()
+	httpHandlers.RegisterAdminRoutes(r, nil, services.NewContributorService(new(MockContributorRepository)))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/admin/rate-limits", nil))
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+}
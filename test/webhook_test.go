@@ -0,0 +1,218 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	httpHandlers "github.com/olusolaa/github-monitor/internal/adapters/http"
+	"github.com/olusolaa/github-monitor/internal/core/domain"
+	"github.com/olusolaa/github-monitor/internal/core/services"
+)
+
+type MockWebhookDeliveryRepository struct{ mock.Mock }
+
+func (m *MockWebhookDeliveryRepository) Claim(ctx context.Context, delivery *domain.WebhookDelivery) (bool, error) {
+	args := m.Called(ctx, delivery)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) Finish(ctx context.Context, deliveryID, status, errMsg string) error {
+	args := m.Called(ctx, deliveryID, status, errMsg)
+	return args.Error(0)
+}
+
+type MockWebhookService struct{ mock.Mock }
+
+func (m *MockWebhookService) HandleDelivery(ctx context.Context, deliveryID, event string, payload []byte) (bool, error) {
+	args := m.Called(ctx, deliveryID, event, payload)
+	return args.Bool(0), args.Error(1)
+}
+
+const pushPayload = `{
+  "ref": "refs/heads/main",
+  "commits": [{
+    "id": "abc123",
+    "message": "Fix bug",
+    "timestamp": "2024-08-06T10:00:00Z",
+    "tree_id": "tree123",
+    "url": "https://github.com/owner/repo/commit/abc123",
+    "author": {"name": "John Roe", "email": "john@example.com", "username": "jroe"},
+    "committer": {"name": "Jane Doe", "email": "jane@example.com"}
+  }],
+  "repository": {
+    "name": "repo",
+    "owner": {"login": "owner", "name": "owner"},
+    "default_branch": "main",
+    "created_at": 1700000000,
+    "git_commits_url": "https://api.github.com/repos/owner/repo/git/commits{/sha}"
+  }
+}`
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookHandler_RejectsInvalidSignature(t *testing.T) {
+	mockWebhookService := new(MockWebhookService)
+	r := chi.NewRouter()
+	httpHandlers.RegisterWebhookRoutes(r, mockWebhookService, "secret")
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewBufferString(pushPayload))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-GitHub-Delivery", "delivery-1")
+	req.Header.Set("X-Hub-Signature-256", sign("wrong", []byte(pushPayload)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockWebhookService.AssertNotCalled(t, "HandleDelivery")
+}
+
+func TestWebhookHandler_DispatchesSignedDelivery(t *testing.T) {
+	mockWebhookService := new(MockWebhookService)
+	mockWebhookService.On("HandleDelivery", mock.Anything, "delivery-1", "push", []byte(pushPayload)).Return(true, nil)
+	r := chi.NewRouter()
+	httpHandlers.RegisterWebhookRoutes(r, mockWebhookService, "secret")
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewBufferString(pushPayload))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-GitHub-Delivery", "delivery-1")
+	req.Header.Set("X-Hub-Signature-256", sign("secret", []byte(pushPayload)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockWebhookService.AssertExpectations(t)
+}
+
+func TestWebhookService_HandlePush(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockCommitRepo := new(MockCommitRepository)
+	mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+	commitService := services.NewCommitService(new(MockGitHubService), mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	service := services.NewWebhookService(mockRepoService, commitService, mockDeliveryRepo)
+
+	expectedCommits := []domain.Commit{{
+		RepositoryID:   7,
+		Hash:           "abc123",
+		Message:        "Fix bug",
+		AuthorName:     "John Roe",
+		AuthorEmail:    "john@example.com",
+		AuthorLogin:    "jroe",
+		CommitterName:  "Jane Doe",
+		CommitterEmail: "jane@example.com",
+		CommitDate:     time.Date(2024, 8, 6, 10, 0, 0, 0, time.UTC),
+		URL:            "https://api.github.com/repos/owner/repo/git/commits/abc123",
+		TreeSHA:        "tree123",
+		HTMLURL:        "https://github.com/owner/repo/commit/abc123",
+		Branch:         "main",
+	}}
+
+	mockDeliveryRepo.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+	mockDeliveryRepo.On("Finish", mock.Anything, "delivery-1", domain.WebhookStatusProcessed, "").Return(nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 7, Name: "repo", Owner: "owner"}, nil)
+	mockCommitRepo.On("Save", mock.Anything, expectedCommits).Return(nil)
+
+	processed, err := service.HandleDelivery(context.Background(), "delivery-1", "push", []byte(pushPayload))
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	mockDeliveryRepo.AssertExpectations(t)
+	mockCommitRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_IgnoresPushToUntrackedBranch(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockCommitRepo := new(MockCommitRepository)
+	mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+	commitService := services.NewCommitService(new(MockGitHubService), mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	service := services.NewWebhookService(mockRepoService, commitService, mockDeliveryRepo)
+
+	mockDeliveryRepo.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+	mockDeliveryRepo.On("Finish", mock.Anything, "delivery-1", domain.WebhookStatusIgnored, "").Return(nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 7, Name: "repo", Owner: "owner", Branches: pq.StringArray{"release/*"}}, nil)
+
+	processed, err := service.HandleDelivery(context.Background(), "delivery-1", "push", []byte(pushPayload))
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	mockDeliveryRepo.AssertExpectations(t)
+	mockCommitRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_LeavesForcePushToPolling(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockCommitRepo := new(MockCommitRepository)
+	mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+	commitService := services.NewCommitService(new(MockGitHubService), mockRepoService, mockCommitRepo, new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	service := services.NewWebhookService(mockRepoService, commitService, mockDeliveryRepo)
+	payload := strings.Replace(pushPayload, `"ref": "refs/heads/main",`, `"ref": "refs/heads/main", "forced": true,`, 1)
+
+	mockDeliveryRepo.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+	mockDeliveryRepo.On("Finish", mock.Anything, "delivery-1", domain.WebhookStatusIgnored, "").Return(nil)
+	mockRepoService.On("GetRepository", mock.Anything, "repo", "owner").Return(&domain.Repository{ID: 7, Name: "repo", Owner: "owner"}, nil)
+
+	processed, err := service.HandleDelivery(context.Background(), "delivery-1", "push", []byte(payload))
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	mockDeliveryRepo.AssertExpectations(t)
+	mockCommitRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_SkipsDuplicateDelivery(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+	commitService := services.NewCommitService(new(MockGitHubService), mockRepoService, new(MockCommitRepository), new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	service := services.NewWebhookService(mockRepoService, commitService, mockDeliveryRepo)
+
+	mockDeliveryRepo.On("Claim", mock.Anything, mock.Anything).Return(false, nil)
+
+	processed, err := service.HandleDelivery(context.Background(), "delivery-1", "push", []byte(pushPayload))
+
+	assert.NoError(t, err)
+	assert.False(t, processed)
+	mockRepoService.AssertNotCalled(t, "GetRepository")
+}
+
+func TestWebhookService_RenamesRepository(t *testing.T) {
+	mockRepoService := new(MockRepositoryService)
+	mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+	commitService := services.NewCommitService(new(MockGitHubService), mockRepoService, new(MockCommitRepository), new(MockBackfillJobRepository), make(chan int64), make(chan int64), "", "")
+	service := services.NewWebhookService(mockRepoService, commitService, mockDeliveryRepo)
+	payload := `{
+  "action": "renamed",
+  "changes": {"repository": {"name": {"from": "old-repo"}}},
+  "repository": {"name": "repo", "owner": {"login": "owner"}, "default_branch": "main"}
+}`
+
+	mockDeliveryRepo.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+	mockDeliveryRepo.On("Finish", mock.Anything, "delivery-1", domain.WebhookStatusProcessed, "").Return(nil)
+	mockRepoService.On("RenameRepository", mock.Anything, "owner", "old-repo", "repo").Return(&domain.Repository{ID: 7, Name: "repo", Owner: "owner"}, nil)
+	mockRepoService.On("UpsertRepository", mock.Anything, mock.MatchedBy(func(repo *domain.Repository) bool {
+		return repo.Owner == "owner" && repo.Name == "repo"
+	})).Return(nil)
+
+	processed, err := service.HandleDelivery(context.Background(), "delivery-1", "repository", []byte(payload))
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	mockRepoService.AssertExpectations(t)
+	mockDeliveryRepo.AssertExpectations(t)
+	mockRepoService.AssertNotCalled(t, "GetRepository", mock.Anything, mock.Anything, mock.Anything)
+}